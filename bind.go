@@ -0,0 +1,103 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"encoding/xml"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// bodyKey is the context key under which Bind stores the decoded body of type T.
+type bodyKey[T any] struct{}
+
+// bindRule is the final rule of Bind that decodes the validated request body into T.
+type bindRule[T any] struct{}
+
+// Bind creates a new Validator middleware that, once the request passed Config.Rules, decodes the request body
+// into a new T and stores it in the context for Body. JSON request bodies are decoded with the JSONDecoder of the app,
+// XML request bodies with encoding/xml, MessagePack, CBOR, YAML, TOML, and registered request bodies by the json tags of T,
+// and other request bodies, such as forms, with c.BodyParser.
+// The built-in rules share a single decoded document of the request body, and T is decoded once after they passed.
+// Bodies rejected by the rules are never decoded into T, and a body that cannot be decoded into T is rejected
+// through the ErrorHandler, so the handler can rely on Body returning a value. With report-only mode, bodies that
+// violated a rule are passed to the handler without being decoded, and Body returns nil for them.
+//
+// Example:
+//
+//	type Login struct {
+//		Username string `json:"username" xml:"username" form:"username"`
+//	}
+//
+//	app.Post("/login", validator.Bind[Login](validator.Config{
+//		Rules: []validator.Restrictor{
+//			validator.RestrictUnicode{Fields: []string{"username"}},
+//		},
+//	}), func(c *fiber.Ctx) error {
+//		login := validator.Body[Login](c)
+//		// ...
+//	})
+func Bind[T any](config ...Config) fiber.Handler {
+	return newHandler(configDefault(config...), bindRule[T]{})
+}
+
+// Body returns the request body decoded by Bind, or nil if Bind[T] did not run for the request
+// or the request body violated a report-only rule.
+func Body[T any](c *fiber.Ctx) *T {
+	body, _ := c.Locals(bodyKey[T]{}).(*T)
+	return body
+}
+
+// Restrict implements the Restrictor interface for bindRule.
+func (bindRule[T]) Restrict(c *fiber.Ctx) error {
+	body := new(T)
+	format := bodyFormatOf(c)
+	switch codec, ok := codecOf(format); {
+	case ok:
+		if err := codec.unmarshal(c.Body(), body); err != nil {
+			return codec.invalidBody(err)
+		}
+	case format == formatJSON:
+		if err := c.App().Config().JSONDecoder(c.Body(), body); err != nil {
+			return errInvalidJSONBody(err)
+		}
+	case format == formatXML:
+		if err := xml.Unmarshal(c.Body(), body); err != nil {
+			return errInvalidXMLBody(err)
+		}
+	default:
+		if err := c.BodyParser(body); err != nil {
+			return errInvalidBody(err)
+		}
+	}
+	c.Locals(bodyKey[T]{}, body)
+	return nil
+}
+
+// Name implements the NamedRestrictor interface for bindRule.
+func (bindRule[T]) Name() string {
+	return "Bind"
+}
+
+// requestLevel implements the requestRestrictor interface for bindRule.
+func (bindRule[T]) requestLevel() {}
+
+// alwaysEnforced implements the alwaysEnforcedRestrictor interface for bindRule.
+func (bindRule[T]) alwaysEnforced() {}
+
+// bindsBody implements the bindingRestrictor interface for bindRule.
+func (bindRule[T]) bindsBody() {}
+
+// bindingRestrictor is implemented by the final rule of Bind, which is skipped for request bodies
+// with reported violations.
+type bindingRestrictor interface {
+	bindsBody()
+}
+
+// isBinding reports whether the rule decodes the request body for Body.
+func isBinding(rule Restrictor) bool {
+	_, ok := rule.(bindingRestrictor)
+	return ok
+}
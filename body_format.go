@@ -0,0 +1,399 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// bodyFormat identifies how a request body is decoded by the built-in rules.
+type bodyFormat int
+
+const (
+	// formatOther is a request body of another content type, which is only scanned as text in legacy text scan mode.
+	formatOther bodyFormat = iota
+	formatJSON
+	formatXML
+	formatMsgPack
+	formatCBOR
+	formatYAML
+	formatTOML
+	formatNDJSON
+)
+
+// nextCustomFormat is the format of the next media type registered with RegisterMediaType.
+var nextCustomFormat = formatNDJSON + 1
+
+// mediaTypeFormats maps media types to the format of their request bodies.
+var mediaTypeFormats = map[string]bodyFormat{
+	fiber.MIMEApplicationJSON: formatJSON,
+	fiber.MIMEApplicationXML:  formatXML,
+	fiber.MIMETextXML:         formatXML,
+	MIMEApplicationMsgPack:    formatMsgPack,
+	mimeApplicationXMsgPack:   formatMsgPack,
+	mimeApplicationVndMsgPack: formatMsgPack,
+	MIMEApplicationCBOR:       formatCBOR,
+	MIMEApplicationYAML:       formatYAML,
+	mimeApplicationXYAML:      formatYAML,
+	mimeTextYAML:              formatYAML,
+	MIMEApplicationTOML:       formatTOML,
+	MIMEApplicationNDJSON:     formatNDJSON,
+	mimeApplicationJSONL:      formatNDJSON,
+	mimeApplicationXJSONLines: formatNDJSON,
+}
+
+// suffixFormats maps structured syntax suffixes (RFC 6838), such as the "json" of "application/vnd.api+json",
+// to the format of their request bodies.
+var suffixFormats = map[string]bodyFormat{
+	"json": formatJSON,
+	"xml":  formatXML,
+	"cbor": formatCBOR,
+	"yaml": formatYAML,
+}
+
+// mediaTypesMu guards mediaTypeFormats, bodyCodecs, and nextCustomFormat, which RegisterMediaType extends.
+var mediaTypesMu sync.RWMutex
+
+// bodyFormatOf determines the format of the request body from its Content-Type header.
+func bodyFormatOf(c *fiber.Ctx) bodyFormat {
+	return mediaTypeFormat(c.Get(fiber.HeaderContentType))
+}
+
+// mediaTypeFormat determines the format of a request body from its content type, which is parsed as defined in RFC 9110.
+// Media types are matched case-insensitively and regardless of their parameters, and media types that are not known
+// are matched by their structured syntax suffix.
+func mediaTypeFormat(contentType string) bodyFormat {
+	mediaTypesMu.RLock()
+	defer mediaTypesMu.RUnlock()
+
+	// Most requests send a known media type without parameters, which does not need to be parsed.
+	if format, ok := mediaTypeFormats[contentType]; ok {
+		return format
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return formatOther
+	}
+	if format, ok := mediaTypeFormats[mediaType]; ok {
+		return format
+	}
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		if format, ok := suffixFormats[mediaType[i+1:]]; ok {
+			return format
+		}
+	}
+	return formatOther
+}
+
+// RegisterMediaType makes request bodies of a media type available to the built-in rules. The unmarshal function decodes
+// a request body into v, which is either a pointer to an interface{} or, for Bind, a pointer to the bound type.
+// Request bodies must decode into an object, and their fields are validated like the fields of JSON request bodies.
+// Error responses to requests of the media type are sent as JSON.
+//
+// Media types are matched case-insensitively and regardless of their parameters. RegisterMediaType panics if the
+// media type is invalid or has parameters, if it is already known, or if unmarshal is nil.
+// It is intended to be called from an init function.
+//
+// Example:
+//
+//	func init() {
+//		validator.RegisterMediaType("application/x-protobuf+json", protojsonUnmarshal)
+//	}
+func RegisterMediaType(mediaType string, unmarshal func(data []byte, v interface{}) error) {
+	if unmarshal == nil {
+		panic("validator: RegisterMediaType unmarshal is nil")
+	}
+	parsed, params, err := mime.ParseMediaType(mediaType)
+	if err != nil || len(params) > 0 {
+		panic("validator: RegisterMediaType called with an invalid media type " + mediaType)
+	}
+
+	mediaTypesMu.Lock()
+	defer mediaTypesMu.Unlock()
+
+	if _, dup := mediaTypeFormats[parsed]; dup {
+		panic("validator: RegisterMediaType called twice for media type " + parsed)
+	}
+	format := nextCustomFormat
+	nextCustomFormat++
+	mediaTypeFormats[parsed] = format
+	bodyCodecs[format] = bodyCodec{
+		mediaType: fiber.MIMEApplicationJSON,
+		unmarshal: unmarshal,
+		marshal:   json.Marshal,
+		invalidBody: func(cause error) *Error {
+			e := errInvalidBody(cause)
+			e.Err = &BodyParseError{Format: parsed, Err: cause}
+			return e
+		},
+	}
+}
+
+// codecOf returns the codec of a format, if it has one.
+func codecOf(format bodyFormat) (bodyCodec, bool) {
+	mediaTypesMu.RLock()
+	defer mediaTypesMu.RUnlock()
+	codec, ok := bodyCodecs[format]
+	return codec, ok
+}
+
+// bodyCodec decodes request bodies and encodes error responses of a format other than JSON and XML
+// that shares the document model of JSON.
+type bodyCodec struct {
+	mediaType   string
+	unmarshal   func(data []byte, v interface{}) error
+	marshal     func(v interface{}) ([]byte, error)
+	invalidBody func(cause error) *Error
+}
+
+// bodyCodecs are the codecs of the formats other than JSON and XML, by format.
+var bodyCodecs = map[bodyFormat]bodyCodec{
+	formatMsgPack: {
+		mediaType:   MIMEApplicationMsgPack,
+		unmarshal:   unmarshalMsgPack,
+		marshal:     marshalMsgPack,
+		invalidBody: errInvalidMsgPackBody,
+	},
+	formatCBOR: {
+		mediaType:   MIMEApplicationCBOR,
+		unmarshal:   cbor.Unmarshal,
+		marshal:     cborEncMode.Marshal,
+		invalidBody: errInvalidCBORBody,
+	},
+	formatYAML: {
+		mediaType:   MIMEApplicationYAML,
+		unmarshal:   unmarshalYAML,
+		marshal:     yaml.Marshal,
+		invalidBody: errInvalidYAMLBody,
+	},
+	formatTOML: {
+		mediaType:   MIMEApplicationTOML,
+		unmarshal:   unmarshalTOML,
+		marshal:     toml.Marshal,
+		invalidBody: errInvalidTOMLBody,
+	},
+}
+
+// maxYAMLAliasNodes is the maximum number of nodes that the aliases of a YAML request body may add when they are expanded.
+// It prevents small documents with nested aliases ("billion laughs") from expanding into huge documents.
+const maxYAMLAliasNodes = 10000
+
+// yamlSizeCap caps the expanded size of a YAML node while counting, so that the count cannot overflow.
+const yamlSizeCap = 1 << 40
+
+// cborEncMode encodes error responses with sorted map keys, so that they are deterministic.
+var cborEncMode, _ = cbor.CoreDetEncOptions().EncMode()
+
+// unmarshalMsgPack decodes MessagePack data. Integers are decoded as int64 or uint64,
+// and struct fields are matched by their json tags, like the other formats.
+func unmarshalMsgPack(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.UseLooseInterfaceDecoding(true)
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// marshalMsgPack encodes a value as MessagePack with sorted map keys, using the json tags of struct fields.
+func marshalMsgPack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetSortMapKeys(true)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalYAML decodes a single YAML document, after checking that its aliases do not expand into more than
+// maxYAMLAliasNodes additional nodes. The document is decoded through JSON, so that struct fields are matched by
+// their json tags and the values have the types of the JSON document model.
+func unmarshalYAML(data []byte, v interface{}) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	var next yaml.Node
+	if err := dec.Decode(&next); err == nil {
+		return errors.New("request body contains more than one document")
+	} else if !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	if err := checkYAMLAliases(&doc); err != nil {
+		return err
+	}
+
+	var raw interface{}
+	if err := doc.Decode(&raw); err != nil {
+		return err
+	}
+	normalized, err := normalizeDocument(raw)
+	if err != nil {
+		return err
+	}
+	return remarshalJSON(normalized, v)
+}
+
+// checkYAMLAliases counts the nodes of a YAML document with its aliases expanded, without expanding them.
+func checkYAMLAliases(doc *yaml.Node) error {
+	sizes := make(map[*yaml.Node]int)
+	var size func(n *yaml.Node) int
+	size = func(n *yaml.Node) int {
+		if s, ok := sizes[n]; ok {
+			return s
+		}
+		// A node that contains itself through an alias is rejected when it is decoded.
+		sizes[n] = 0
+
+		s := 1
+		if n.Kind == yaml.AliasNode && n.Alias != nil {
+			s += size(n.Alias)
+		}
+		for _, child := range n.Content {
+			s += size(child)
+		}
+		if s > yamlSizeCap {
+			s = yamlSizeCap
+		}
+		sizes[n] = s
+		return s
+	}
+
+	if expanded := size(doc); expanded-len(sizes) > maxYAMLAliasNodes {
+		return fmt.Errorf("aliases expand to more than %d nodes", maxYAMLAliasNodes)
+	}
+	return nil
+}
+
+// unmarshalTOML decodes a TOML document. Like YAML, the document is decoded through JSON.
+func unmarshalTOML(data []byte, v interface{}) error {
+	var raw map[string]interface{}
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return remarshalJSON(raw, v)
+}
+
+// remarshalJSON converts a decoded document into v by encoding it as JSON and decoding it again.
+func remarshalJSON(raw interface{}, v interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// normalizeDocument converts the mappings of a decoded YAML, MessagePack, or CBOR document with non-string keys,
+// such as response codes, to map[string]interface{}. It returns an error if two keys of a mapping have the same
+// string form, such as 1 and "1", which would otherwise silently replace one another.
+func normalizeDocument(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			normalized, err := normalizeDocument(child)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = normalized
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			key := fmt.Sprint(k)
+			if _, ok := m[key]; ok {
+				return nil, fmt.Errorf("duplicate key %q", key)
+			}
+			normalized, err := normalizeDocument(child)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = normalized
+		}
+		return m, nil
+	case []interface{}:
+		for i, child := range v {
+			normalized, err := normalizeDocument(child)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = normalized
+		}
+		return v, nil
+	}
+	return v, nil
+}
+
+// documentKey is the context key of the decoded request body that the rules of a request share.
+type documentKey struct{}
+
+// decodedDocument is a request body decoded by decodeDocument, with a copy of the data it was decoded from.
+type decodedDocument struct {
+	format bodyFormat
+	data   []byte
+	body   map[string]interface{}
+}
+
+// decodeDocument decodes a JSON, MessagePack, CBOR, YAML, TOML, or registered request body into the document model of encoding/json,
+// where objects are map[string]interface{}, arrays are []interface{}, and numbers are float64.
+// The request body must be an object.
+//
+// The decoded body is shared by the rules of the request, so the request body is decoded once. Rules must not modify it.
+// Bodies that replace the request body while parts of it are validated, such as NDJSON lines, are not shared.
+func decodeDocument(c *fiber.Ctx, format bodyFormat) (map[string]interface{}, error) {
+	swapped := c.Locals(requestContentTypeKey{}) != nil
+	if doc, ok := c.Locals(documentKey{}).(*decodedDocument); ok && !swapped && doc.format == format && bytes.Equal(doc.data, c.Body()) {
+		return doc.body, nil
+	}
+
+	body, err := decodeBody(c, format)
+	if err != nil || swapped {
+		return body, err
+	}
+	c.Locals(documentKey{}, &decodedDocument{format: format, data: append([]byte(nil), c.Body()...), body: body})
+	return body, nil
+}
+
+// decodeBody decodes the request body into the document model of encoding/json.
+func decodeBody(c *fiber.Ctx, format bodyFormat) (map[string]interface{}, error) {
+	if format == formatJSON {
+		var body map[string]interface{}
+		if err := c.App().Config().JSONDecoder(c.Body(), &body); err != nil {
+			return nil, errInvalidJSONBody(err)
+		}
+		return body, nil
+	}
+
+	codec, _ := codecOf(format)
+	var raw interface{}
+	if err := codec.unmarshal(c.Body(), &raw); err != nil {
+		return nil, codec.invalidBody(err)
+	}
+	normalized, err := normalizeDocument(raw)
+	if err != nil {
+		return nil, codec.invalidBody(err)
+	}
+	body, ok := normalizeNumbers(normalized).(map[string]interface{})
+	if !ok {
+		return nil, codec.invalidBody(errors.New("request body is not an object"))
+	}
+	return body, nil
+}
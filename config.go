@@ -4,7 +4,12 @@
 
 package validator
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/text/language"
+)
 
 // Restrictor is an interface for defining custom validation rules.
 type Restrictor interface {
@@ -26,19 +31,140 @@ type Config struct {
 	// Optional. Default: DefaultErrorHandler
 	ErrorHandler func(c *fiber.Ctx, err error) error
 
-	// ContextKey is the key used to store the validation result in the context.
+	// ContextKey is the key used to store the validation *Result in the context, in addition to ResultFrom.
 	//
 	// Note: This is most useful in advanced use cases for communicating internal context within the application,
 	// especially for securing authentication and authorization.
 	//
 	// Optional. Default: nil
 	ContextKey string
+
+	// RecordValues records the sanitized values of the checked fields that passed every rule in Result.Values.
+	// Long strings are truncated, and the values of fields whose names suggest a secret, such as passwords
+	// and tokens, are masked, since the values are visible to every later handler.
+	//
+	// Optional. Default: false
+	RecordValues bool
+
+	// JSONLimits is used to token-scan JSON request bodies before any rule decodes them,
+	// rejecting duplicate keys and documents that are nested too deeply or are too large.
+	//
+	// Optional. Default: nil
+	JSONLimits *JSONLimits
+
+	// AllowedContentTypes lists the media types, or media ranges such as "application/*", of the request bodies
+	// accepted by the middleware. Request bodies of other media types, or without a Content-Type header,
+	// are rejected with a 415 status through the ErrorHandler before any rule runs. Requests without a body are not checked.
+	// When set, the built-in rules no longer scan request bodies of content types that they do not decode as text,
+	// but reject them with a 415 status unless LegacyTextScan is set.
+	//
+	// Optional. Default: nil (every media type is accepted)
+	AllowedContentTypes []string
+
+	// LegacyTextScan keeps the built-in rules scanning request bodies of content types other than JSON, XML, and the other
+	// decoded formats, such as text/plain or form request bodies, for their fields as text when AllowedContentTypes is set
+	// on the middleware or on the rule. The scan searches the raw body for the field names and can both miss and misreport
+	// values, so with AllowedContentTypes such request bodies are otherwise rejected with a 415 status.
+	// The built-in rules also accept their own LegacyTextScan.
+	//
+	// Optional. Default: false
+	LegacyTextScan bool
+
+	// Transcode transcodes text request bodies declared in another charset to UTF-8 before the rules run,
+	// and rejects request bodies in an unknown or disallowed charset with a 415 status.
+	// Unless Transcode sets its own MaxBodySize, the transcoded body is limited to MaxBodySize as well.
+	//
+	// Optional. Default: nil (request bodies are validated as they are)
+	Transcode *Transcode
+
+	// MaxBodySize is the maximum allowed size of the request body in bytes.
+	// Larger requests are rejected with a 413 status before any rule parses the body,
+	// and requests whose Content-Length header already exceeds the limit are rejected early.
+	// Compressed request bodies are checked before and after decoding, and are decoded only up to the limit.
+	//
+	// Optional. Default: 0 (no limit)
+	MaxBodySize int
+
+	// NDJSONMaxErrors is the number of invalid lines of an NDJSON request body that are collected by a rule
+	// before the request is rejected. Every line is validated as a separate JSON request body, and the error of
+	// the first invalid line is reported with the "line" param and the others in the "errors" param.
+	//
+	// Optional. Default: 0 (the request is rejected at the first invalid line)
+	NDJSONMaxErrors int
+
+	// ReportOnly runs every rule but never rejects the request. Violations are stored in Result.Reported
+	// and passed to OnReport, and c.Next is always called.
+	// This allows new rules to be observed in production before they are enforced.
+	// MaxBodySize, JSONLimits, AllowedContentTypes, and Transcode are always enforced.
+	//
+	// Optional. Default: false
+	ReportOnly bool
+
+	// EnforcePercentage is the percentage (0-100) of requests for which report-only violations are
+	// enforced anyway, allowing a gradual rollout. It applies to ReportOnly and to rules that set
+	// their own ReportOnly flag.
+	//
+	// Optional. Default: 0
+	EnforcePercentage int
+
+	// OnReport is called for every violation that is reported instead of enforced.
+	//
+	// Optional. Default: nil
+	OnReport func(c *fiber.Ctx, err error)
+
+	// OnRuleEvaluated is called after every rule evaluation with the rule identity, its fields,
+	// the time it took, and the violation, if any.
+	//
+	// Optional. Default: nil
+	OnRuleEvaluated func(c *fiber.Ctx, e Evaluation)
+
+	// OnSuccess is called when the request passed validation, with the total validation duration.
+	//
+	// Optional. Default: nil
+	OnSuccess func(c *fiber.Ctx, duration time.Duration)
+
+	// OnFailure is called with the evaluation of the rule that rejected the request.
+	//
+	// Optional. Default: nil
+	OnFailure func(c *fiber.Ctx, e Evaluation)
+
+	// Localizer translates the messages of rejected requests by error code before they are passed to the ErrorHandler.
+	//
+	// Optional. Default: nil (messages are sent in English)
+	Localizer *Localizer
+
+	// Language returns the preferred language of the request for the Localizer.
+	//
+	// Optional. Default: the languages of the Accept-Language header
+	Language func(c *fiber.Ctx) language.Tag
+
+	// Reloader allows the rules to be swapped at runtime without restarting the server.
+	// A Reloader can only be used by a single middleware. It is ignored by Registry.
+	//
+	// Optional. Default: nil
+	Reloader *Reloader
 }
 
 // ConfigDefault is the default configuration for the Validator middleware.
 var ConfigDefault = Config{
-	Rules:        nil,
-	Next:         nil,
-	ErrorHandler: DefaultErrorHandler,
-	ContextKey:   "",
+	Rules:               nil,
+	Next:                nil,
+	ErrorHandler:        DefaultErrorHandler,
+	ContextKey:          "",
+	RecordValues:        false,
+	JSONLimits:          nil,
+	AllowedContentTypes: nil,
+	LegacyTextScan:      false,
+	Transcode:           nil,
+	MaxBodySize:         0,
+	NDJSONMaxErrors:     0,
+	ReportOnly:          false,
+	EnforcePercentage:   0,
+	OnReport:            nil,
+	OnRuleEvaluated:     nil,
+	OnSuccess:           nil,
+	OnFailure:           nil,
+	Localizer:           nil,
+	Language:            nil,
+	Reloader:            nil,
 }
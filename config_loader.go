@@ -0,0 +1,751 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleConstructor creates a Restrictor from its declaration in a configuration file.
+// The decode function decodes the parameters of the rule into v, which should be a pointer to a struct
+// whose fields are tagged with their yaml names. Parameters that do not match a field are rejected.
+type RuleConstructor func(decode func(v interface{}) error) (Restrictor, error)
+
+// ConfigError is returned by LoadConfig when a configuration file is invalid,
+// with the location of the offending value.
+type ConfigError struct {
+	// Line is the line of the offending value, starting at 1.
+	Line int
+
+	// Column is the column of the offending value, starting at 1.
+	Column int
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface for ConfigError.
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("validator: line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	ruleConstructorsMu sync.RWMutex
+	ruleConstructors   = map[string]RuleConstructor{
+		"RestrictUnicode":      newRestrictUnicode,
+		"RestrictStringLength": newRestrictStringLength,
+		"RestrictNumberOnly":   newRestrictNumberOnly,
+		"RestrictFiles":        newRestrictFiles,
+		"RestrictImage":        newRestrictImage,
+		"RestrictType":         newRestrictType,
+	}
+)
+
+func init() {
+	// RestrictArray loads its element rules with loadRule, which refers to ruleConstructors.
+	ruleConstructors["RestrictArray"] = newRestrictArray
+}
+
+// RegisterRule makes a rule type available to LoadConfig under the given name.
+// It panics if the constructor is nil or the name is already registered, including the names of the built-in rules.
+func RegisterRule(name string, constructor RuleConstructor) {
+	ruleConstructorsMu.Lock()
+	defer ruleConstructorsMu.Unlock()
+
+	if constructor == nil {
+		panic("validator: RegisterRule constructor is nil")
+	}
+	if _, ok := ruleConstructors[name]; ok {
+		panic("validator: RegisterRule called twice for rule " + name)
+	}
+	ruleConstructors[name] = constructor
+}
+
+// configSpec is the declaration of a Config in a configuration file.
+type configSpec struct {
+	ContextKey          string          `yaml:"contextKey"`
+	RecordValues        bool            `yaml:"recordValues"`
+	MaxBodySize         int             `yaml:"maxBodySize"`
+	AllowedContentTypes []string        `yaml:"allowedContentTypes"`
+	LegacyTextScan      bool            `yaml:"legacyTextScan"`
+	ReportOnly          bool            `yaml:"reportOnly"`
+	EnforcePercentage   int             `yaml:"enforcePercentage"`
+	JSONLimits          *jsonLimitsSpec `yaml:"jsonLimits"`
+	Transcode           *transcodeSpec  `yaml:"transcode"`
+	Rules               []yaml.Node     `yaml:"rules"`
+}
+
+// transcodeSpec is the declaration of Transcode in a configuration file.
+type transcodeSpec struct {
+	AllowedCharsets []string `yaml:"allowedCharsets"`
+}
+
+// jsonLimitsSpec is the declaration of JSONLimits in a configuration file.
+type jsonLimitsSpec struct {
+	MaxDepth            *int `yaml:"maxDepth"`
+	MaxKeys             *int `yaml:"maxKeys"`
+	MaxArrayLength      *int `yaml:"maxArrayLength"`
+	MaxStringLength     *int `yaml:"maxStringLength"`
+	AllowDuplicateKeys  bool `yaml:"allowDuplicateKeys"`
+	CaseInsensitiveKeys bool `yaml:"caseInsensitiveKeys"`
+}
+
+// ruleSpec is the declaration of the options shared by the built-in rules in a configuration file.
+type ruleSpec struct {
+	Fields              []string                     `yaml:"fields"`
+	MaxBodySize         int                          `yaml:"maxBodySize"`
+	AllowedContentTypes []string                     `yaml:"allowedContentTypes"`
+	LegacyTextScan      bool                         `yaml:"legacyTextScan"`
+	ReportOnly          bool                         `yaml:"reportOnly"`
+	Message             string                       `yaml:"message"`
+	Status              int                          `yaml:"status"`
+	FieldOverrides      map[string]errorOverrideSpec `yaml:"fieldOverrides"`
+}
+
+// errorOverrideSpec is the declaration of an ErrorOverride in a configuration file.
+type errorOverrideSpec struct {
+	Message string `yaml:"message"`
+	Status  int    `yaml:"status"`
+}
+
+// LoadConfig reads a Config from a YAML or JSON configuration file.
+// Options that cannot be expressed in a file, such as the ErrorHandler and the hooks, can be set on the returned Config.
+//
+// Rules are declared with their type name and parameters. The built-in rules are available under their Go type names,
+// and other rules can be made available with RegisterRule. Unknown rule types, unknown parameters,
+// and invalid values are reported as a *ConfigError with the line and column of the offending value.
+//
+// Example:
+//
+//	maxBodySize: 4096
+//	rules:
+//	  - type: RestrictUnicode
+//	    fields: [username, email]
+//	  - type: RestrictStringLength
+//	    fields: [username]
+//	    maxLength: 32
+//	  - type: RestrictNumberOnly
+//	    fields: [age]
+//	    max: 150
+//	    status: 422
+func LoadConfig(r io.Reader) (Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(jsonTabsToSpaces(data), &doc); err != nil {
+		return Config{}, fmt.Errorf("validator: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return Config{}, nil
+	}
+
+	var spec configSpec
+	root := doc.Content[0]
+	if err := decodeNode(root, reflect.ValueOf(&spec).Elem()); err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{
+		ContextKey:          spec.ContextKey,
+		RecordValues:        spec.RecordValues,
+		MaxBodySize:         spec.MaxBodySize,
+		AllowedContentTypes: spec.AllowedContentTypes,
+		LegacyTextScan:      spec.LegacyTextScan,
+		ReportOnly:          spec.ReportOnly,
+		EnforcePercentage:   spec.EnforcePercentage,
+	}
+	if cfg.MaxBodySize < 0 {
+		return Config{}, nodeError(mappingValue(root, "maxBodySize"), errors.New("maxBodySize must not be negative"))
+	}
+	for i, mediaType := range cfg.AllowedContentTypes {
+		if err := validMediaRange(mediaType); err != nil {
+			return Config{}, nodeError(mappingValue(root, "allowedContentTypes").Content[i], err)
+		}
+	}
+	if cfg.EnforcePercentage < 0 || cfg.EnforcePercentage > 100 {
+		return Config{}, nodeError(mappingValue(root, "enforcePercentage"), errors.New("enforcePercentage must be between 0 and 100"))
+	}
+	if l := spec.JSONLimits; l != nil {
+		for _, limit := range []struct {
+			name  string
+			value *int
+		}{
+			{"maxDepth", l.MaxDepth},
+			{"maxKeys", l.MaxKeys},
+			{"maxArrayLength", l.MaxArrayLength},
+			{"maxStringLength", l.MaxStringLength},
+		} {
+			if err := nonNegative(limit.name, limit.value); err != nil {
+				return Config{}, nodeError(mappingValue(mappingValue(root, "jsonLimits"), limit.name), err)
+			}
+		}
+		cfg.JSONLimits = &JSONLimits{
+			MaxDepth:            l.MaxDepth,
+			MaxKeys:             l.MaxKeys,
+			MaxArrayLength:      l.MaxArrayLength,
+			MaxStringLength:     l.MaxStringLength,
+			AllowDuplicateKeys:  l.AllowDuplicateKeys,
+			CaseInsensitiveKeys: l.CaseInsensitiveKeys,
+		}
+	}
+
+	if t := spec.Transcode; t != nil {
+		for i, charset := range t.AllowedCharsets {
+			if _, _, ok := lookupCharset(charset); !ok {
+				node := mappingValue(mappingValue(root, "transcode"), "allowedCharsets").Content[i]
+				return Config{}, nodeError(node, fmt.Errorf("unknown charset %q", charset))
+			}
+		}
+		cfg.Transcode = &Transcode{AllowedCharsets: t.AllowedCharsets}
+	}
+
+	for i := range spec.Rules {
+		rule, err := loadRule(&spec.Rules[i])
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.Rules = append(cfg.Rules, rule)
+	}
+
+	return cfg, nil
+}
+
+// jsonTabsToSpaces replaces the tabs of a JSON document with spaces, so that it can be parsed as YAML.
+// JSON only allows raw tabs as whitespace between tokens, but YAML forbids them as indentation.
+func jsonTabsToSpaces(data []byte) []byte {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return bytes.ReplaceAll(data, []byte("\t"), []byte(" "))
+	}
+	return data
+}
+
+// loadRule creates a rule from its declaration using the constructor registered for its type.
+func loadRule(node *yaml.Node) (Restrictor, error) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return nil, nodeError(node, errors.New("rule must be a mapping"))
+	}
+
+	typeNode := mappingValue(node, "type")
+	if typeNode == nil {
+		return nil, nodeError(node, errors.New("rule has no type"))
+	}
+	name := typeNode.Value
+
+	ruleConstructorsMu.RLock()
+	constructor, ok := ruleConstructors[name]
+	ruleConstructorsMu.RUnlock()
+	if !ok {
+		return nil, nodeError(typeNode, fmt.Errorf("unknown rule type %q", name))
+	}
+
+	// The parameters are the mapping without its type.
+	params := *node
+	params.Content = nil
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "type" {
+			params.Content = append(params.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+
+	rule, err := constructor(func(v interface{}) error {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return fmt.Errorf("validator: decode of rule %s requires a non-nil pointer", name)
+		}
+		return decodeNode(&params, rv.Elem())
+	})
+	if err != nil {
+		var configErr *ConfigError
+		if errors.As(err, &configErr) {
+			return nil, err
+		}
+		return nil, nodeError(node, fmt.Errorf("%s: %w", name, err))
+	}
+	return rule, nil
+}
+
+// decodeNode decodes a YAML node into v, rejecting mapping keys that do not match a struct field
+// and reporting the location of invalid values.
+func decodeNode(node *yaml.Node, v reflect.Value) error {
+	node = resolveAlias(node)
+	if node.Tag == "!!null" {
+		return nil
+	}
+
+	switch {
+	case v.Type() == reflect.TypeOf(yaml.Node{}):
+		v.Set(reflect.ValueOf(*node))
+		return nil
+	case v.Kind() == reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeNode(node, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case v.Kind() == reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nodeError(node, errors.New("expected a mapping"))
+		}
+		fields := make(map[string]reflect.Value)
+		collectFields(v, fields)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				return nodeError(key, fmt.Errorf("unknown field %q", key.Value))
+			}
+			if err := decodeNode(value, field); err != nil {
+				return err
+			}
+		}
+		return nil
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		if node.Kind != yaml.MappingNode {
+			return nodeError(node, errors.New("expected a mapping"))
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeNode(node.Content[i+1], elem); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(node.Content[i].Value).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+		return nil
+	case v.Kind() == reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return nodeError(node, errors.New("expected a sequence"))
+		}
+		s := reflect.MakeSlice(v.Type(), len(node.Content), len(node.Content))
+		for i, elem := range node.Content {
+			if err := decodeNode(elem, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return nodeError(node, fmt.Errorf("expected a %s value", v.Type()))
+	}
+	if err := node.Decode(v.Addr().Interface()); err != nil {
+		return nodeError(node, fmt.Errorf("invalid %s value %q", v.Type(), node.Value))
+	}
+	return nil
+}
+
+// collectFields collects the fields of a struct by their yaml names, including the fields of inlined structs.
+func collectFields(v reflect.Value, fields map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if opts == "inline" && f.Type.Kind() == reflect.Struct {
+			collectFields(v.Field(i), fields)
+			continue
+		}
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = v.Field(i)
+	}
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil if the key is not present.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolveAlias(node.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// resolveAlias returns the node an alias refers to.
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// nodeError returns a *ConfigError at the location of a node.
+func nodeError(node *yaml.Node, err error) error {
+	return &ConfigError{Line: node.Line, Column: node.Column, Err: err}
+}
+
+// options returns the shared options of a built-in rule, validating them.
+func (s ruleSpec) options() (ErrorOverride, map[string]ErrorOverride, error) {
+	if len(s.Fields) == 0 {
+		return ErrorOverride{}, nil, errors.New("fields must not be empty")
+	}
+	return s.optionalFieldsOptions()
+}
+
+// optionalFieldsOptions returns the shared options of a built-in rule whose fields are optional, validating them.
+func (s ruleSpec) optionalFieldsOptions() (ErrorOverride, map[string]ErrorOverride, error) {
+	if s.MaxBodySize < 0 {
+		return ErrorOverride{}, nil, errors.New("maxBodySize must not be negative")
+	}
+	for _, mediaType := range s.AllowedContentTypes {
+		if err := validMediaRange(mediaType); err != nil {
+			return ErrorOverride{}, nil, err
+		}
+	}
+	if !validStatus(s.Status) {
+		return ErrorOverride{}, nil, fmt.Errorf("invalid status %d", s.Status)
+	}
+
+	var overrides map[string]ErrorOverride
+	for field, o := range s.FieldOverrides {
+		if !validStatus(o.Status) {
+			return ErrorOverride{}, nil, fmt.Errorf("invalid status %d for field %q", o.Status, field)
+		}
+		if overrides == nil {
+			overrides = make(map[string]ErrorOverride, len(s.FieldOverrides))
+		}
+		overrides[field] = ErrorOverride(o)
+	}
+	return ErrorOverride{Message: s.Message, Status: s.Status}, overrides, nil
+}
+
+// validStatus reports whether an optional status code is unset or a valid HTTP status code.
+func validStatus(status int) bool {
+	return status == 0 || (status >= 100 && status <= 599)
+}
+
+// validMediaRange returns an error if an accepted media type is not a media type or a media range without parameters.
+func validMediaRange(mediaType string) error {
+	if _, params, err := mime.ParseMediaType(mediaType); err != nil || len(params) > 0 || !strings.Contains(mediaType, "/") {
+		return fmt.Errorf("invalid media type %q", mediaType)
+	}
+	return nil
+}
+
+// nonNegative returns an error if an optional limit is negative.
+func nonNegative(name string, limit *int) error {
+	if limit != nil && *limit < 0 {
+		return fmt.Errorf("%s must not be negative", name)
+	}
+	return nil
+}
+
+// newRestrictUnicode is the RuleConstructor of RestrictUnicode.
+func newRestrictUnicode(decode func(v interface{}) error) (Restrictor, error) {
+	var spec struct {
+		ruleSpec    `yaml:",inline"`
+		StrictTypes bool `yaml:"strictTypes"`
+	}
+	if err := decode(&spec); err != nil {
+		return nil, err
+	}
+	override, overrides, err := spec.options()
+	if err != nil {
+		return nil, err
+	}
+	return RestrictUnicode{
+		Fields:              spec.Fields,
+		StrictTypes:         spec.StrictTypes,
+		MaxBodySize:         spec.MaxBodySize,
+		AllowedContentTypes: spec.AllowedContentTypes,
+		LegacyTextScan:      spec.LegacyTextScan,
+		ReportOnly:          spec.ReportOnly,
+		Message:             override.Message,
+		Status:              override.Status,
+		FieldOverrides:      overrides,
+	}, nil
+}
+
+// newRestrictStringLength is the RuleConstructor of RestrictStringLength.
+func newRestrictStringLength(decode func(v interface{}) error) (Restrictor, error) {
+	var spec struct {
+		ruleSpec    `yaml:",inline"`
+		MaxLength   *int `yaml:"maxLength"`
+		StrictTypes bool `yaml:"strictTypes"`
+	}
+	if err := decode(&spec); err != nil {
+		return nil, err
+	}
+	override, overrides, err := spec.options()
+	if err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxLength", spec.MaxLength); err != nil {
+		return nil, err
+	}
+	return RestrictStringLength{
+		Fields:              spec.Fields,
+		MaxLength:           spec.MaxLength,
+		StrictTypes:         spec.StrictTypes,
+		MaxBodySize:         spec.MaxBodySize,
+		AllowedContentTypes: spec.AllowedContentTypes,
+		LegacyTextScan:      spec.LegacyTextScan,
+		ReportOnly:          spec.ReportOnly,
+		Message:             override.Message,
+		Status:              override.Status,
+		FieldOverrides:      overrides,
+	}, nil
+}
+
+// newRestrictNumberOnly is the RuleConstructor of RestrictNumberOnly.
+func newRestrictNumberOnly(decode func(v interface{}) error) (Restrictor, error) {
+	var spec struct {
+		ruleSpec  `yaml:",inline"`
+		Max       *int `yaml:"max"`
+		MaxDigits *int `yaml:"maxDigits"`
+	}
+	if err := decode(&spec); err != nil {
+		return nil, err
+	}
+	override, overrides, err := spec.options()
+	if err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxDigits", spec.MaxDigits); err != nil {
+		return nil, err
+	}
+	return RestrictNumberOnly{
+		Fields:              spec.Fields,
+		Max:                 spec.Max,
+		MaxDigits:           spec.MaxDigits,
+		MaxBodySize:         spec.MaxBodySize,
+		AllowedContentTypes: spec.AllowedContentTypes,
+		LegacyTextScan:      spec.LegacyTextScan,
+		ReportOnly:          spec.ReportOnly,
+		Message:             override.Message,
+		Status:              override.Status,
+		FieldOverrides:      overrides,
+	}, nil
+}
+
+// newRestrictFiles is the RuleConstructor of RestrictFiles.
+// The magic bytes of the signatures are declared as hexadecimal strings.
+func newRestrictFiles(decode func(v interface{}) error) (Restrictor, error) {
+	var spec struct {
+		ruleSpec          `yaml:",inline"`
+		MaxFiles          int      `yaml:"maxFiles"`
+		MaxFileSize       int      `yaml:"maxFileSize"`
+		MaxTotalSize      int      `yaml:"maxTotalSize"`
+		AllowedExtensions []string `yaml:"allowedExtensions"`
+		AllowedFileTypes  []string `yaml:"allowedFileTypes"`
+		Signatures        []struct {
+			ContentType string `yaml:"contentType"`
+			Offset      int    `yaml:"offset"`
+			Magic       string `yaml:"magic"`
+		} `yaml:"signatures"`
+	}
+	if err := decode(&spec); err != nil {
+		return nil, err
+	}
+	override, overrides, err := spec.optionalFieldsOptions()
+	if err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxFiles", &spec.MaxFiles); err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxFileSize", &spec.MaxFileSize); err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxTotalSize", &spec.MaxTotalSize); err != nil {
+		return nil, err
+	}
+	for _, fileType := range spec.AllowedFileTypes {
+		if err := validMediaRange(fileType); err != nil {
+			return nil, err
+		}
+	}
+
+	rule := RestrictFiles{
+		Fields:            spec.Fields,
+		MaxFiles:          spec.MaxFiles,
+		MaxFileSize:       spec.MaxFileSize,
+		MaxTotalSize:      spec.MaxTotalSize,
+		AllowedExtensions: spec.AllowedExtensions,
+		AllowedFileTypes:  spec.AllowedFileTypes,
+		MaxBodySize:       spec.MaxBodySize,
+		ReportOnly:        spec.ReportOnly,
+		Message:           override.Message,
+		Status:            override.Status,
+		FieldOverrides:    overrides,
+	}
+	for _, sig := range spec.Signatures {
+		magic, err := hex.DecodeString(sig.Magic)
+		if err != nil || len(magic) == 0 {
+			return nil, fmt.Errorf("invalid magic %q of signature %q", sig.Magic, sig.ContentType)
+		}
+		if sig.Offset < 0 {
+			return nil, fmt.Errorf("offset of signature %q must not be negative", sig.ContentType)
+		}
+		if err := validMediaRange(sig.ContentType); err != nil {
+			return nil, err
+		}
+		rule.Signatures = append(rule.Signatures, FileSignature{ContentType: sig.ContentType, Offset: sig.Offset, Magic: magic})
+	}
+	return rule, nil
+}
+
+// newRestrictImage is the RuleConstructor of RestrictImage.
+func newRestrictImage(decode func(v interface{}) error) (Restrictor, error) {
+	var spec struct {
+		ruleSpec       `yaml:",inline"`
+		AllowedFormats []string `yaml:"allowedFormats"`
+		MaxWidth       int      `yaml:"maxWidth"`
+		MaxHeight      int      `yaml:"maxHeight"`
+		MaxPixels      int      `yaml:"maxPixels"`
+		AllowAnimated  bool     `yaml:"allowAnimated"`
+	}
+	if err := decode(&spec); err != nil {
+		return nil, err
+	}
+	override, overrides, err := spec.optionalFieldsOptions()
+	if err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxWidth", &spec.MaxWidth); err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxHeight", &spec.MaxHeight); err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxPixels", &spec.MaxPixels); err != nil {
+		return nil, err
+	}
+	return RestrictImage{
+		Fields:         spec.Fields,
+		AllowedFormats: spec.AllowedFormats,
+		MaxWidth:       spec.MaxWidth,
+		MaxHeight:      spec.MaxHeight,
+		MaxPixels:      spec.MaxPixels,
+		AllowAnimated:  spec.AllowAnimated,
+		MaxBodySize:    spec.MaxBodySize,
+		ReportOnly:     spec.ReportOnly,
+		Message:        override.Message,
+		Status:         override.Status,
+		FieldOverrides: overrides,
+	}, nil
+}
+
+// newRestrictType is the RuleConstructor of RestrictType.
+// The required type is declared as jsonType, since the type key names the rule.
+func newRestrictType(decode func(v interface{}) error) (Restrictor, error) {
+	var spec struct {
+		ruleSpec `yaml:",inline"`
+		JSONType JSONType `yaml:"jsonType"`
+		Nullable bool     `yaml:"nullable"`
+	}
+	if err := decode(&spec); err != nil {
+		return nil, err
+	}
+	override, overrides, err := spec.options()
+	if err != nil {
+		return nil, err
+	}
+	if !validJSONType(spec.JSONType) {
+		return nil, fmt.Errorf("invalid jsonType %q", spec.JSONType)
+	}
+	return RestrictType{
+		Fields:         spec.Fields,
+		Type:           spec.JSONType,
+		Nullable:       spec.Nullable,
+		MaxBodySize:    spec.MaxBodySize,
+		ReportOnly:     spec.ReportOnly,
+		Message:        override.Message,
+		Status:         override.Status,
+		FieldOverrides: overrides,
+	}, nil
+}
+
+// newRestrictArray is the RuleConstructor of RestrictArray.
+// The element rules are declared under each like the other rules, and their fields may be omitted.
+func newRestrictArray(decode func(v interface{}) error) (Restrictor, error) {
+	var spec struct {
+		ruleSpec    `yaml:",inline"`
+		MinItems    *int        `yaml:"minItems"`
+		MaxItems    *int        `yaml:"maxItems"`
+		UniqueItems bool        `yaml:"uniqueItems"`
+		Each        []yaml.Node `yaml:"each"`
+	}
+	if err := decode(&spec); err != nil {
+		return nil, err
+	}
+	override, overrides, err := spec.options()
+	if err != nil {
+		return nil, err
+	}
+	if err := nonNegative("minItems", spec.MinItems); err != nil {
+		return nil, err
+	}
+	if err := nonNegative("maxItems", spec.MaxItems); err != nil {
+		return nil, err
+	}
+	if spec.MinItems != nil && spec.MaxItems != nil && *spec.MinItems > *spec.MaxItems {
+		return nil, errors.New("minItems must not exceed maxItems")
+	}
+
+	var each []Restrictor
+	for i := range spec.Each {
+		rule, err := loadElementRule(&spec.Each[i])
+		if err != nil {
+			return nil, err
+		}
+		each = append(each, rule)
+	}
+	return RestrictArray{
+		Fields:         spec.Fields,
+		MinItems:       spec.MinItems,
+		MaxItems:       spec.MaxItems,
+		UniqueItems:    spec.UniqueItems,
+		Each:           each,
+		MaxBodySize:    spec.MaxBodySize,
+		ReportOnly:     spec.ReportOnly,
+		Message:        override.Message,
+		Status:         override.Status,
+		FieldOverrides: overrides,
+	}, nil
+}
+
+// loadElementRule loads an element rule of RestrictArray. RestrictArray sets the fields of its element rules,
+// so a placeholder field is declared for rules that omit them.
+func loadElementRule(node *yaml.Node) (Restrictor, error) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode || mappingValue(node, "fields") != nil {
+		return loadRule(node)
+	}
+
+	withFields := *node
+	withFields.Content = append(append([]*yaml.Node(nil), node.Content...),
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "fields", Line: node.Line, Column: node.Column},
+		&yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: node.Line, Column: node.Column, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "items", Line: node.Line, Column: node.Column},
+		}},
+	)
+	return loadRule(&withFields)
+}
@@ -13,6 +13,24 @@ const (
 
 	// ErrInvalidXMLBody represents an error message for an invalid XML request body.
 	ErrInvalidXMLBody = "Invalid XML request body"
+
+	// ErrInvalidMsgPackBody represents an error message for an invalid MessagePack request body.
+	ErrInvalidMsgPackBody = "Invalid MessagePack request body"
+
+	// ErrInvalidCBORBody represents an error message for an invalid CBOR request body.
+	ErrInvalidCBORBody = "Invalid CBOR request body"
+
+	// ErrInvalidYAMLBody represents an error message for an invalid YAML request body.
+	ErrInvalidYAMLBody = "Invalid YAML request body"
+
+	// ErrInvalidTOMLBody represents an error message for an invalid TOML request body.
+	ErrInvalidTOMLBody = "Invalid TOML request body"
+
+	// ErrInvalidNDJSONLine represents an error message for an invalid line of an NDJSON request body.
+	ErrInvalidNDJSONLine = "Line %d: %s"
+
+	// ErrInvalidRequestBody represents an error message for a request body of another content type that cannot be decoded.
+	ErrInvalidRequestBody = "Invalid request body"
 )
 
 const (
@@ -34,6 +52,253 @@ const (
 	ErrFieldsExceedMaximumLength = "The '%s' fields must not exceed the maximum length"
 )
 
+const (
+	// ErrDuplicateJSONKey represents an error message for a JSON object that contains the same key more than once.
+	ErrDuplicateJSONKey = "Duplicate key '%s' in JSON request body"
+
+	// ErrJSONExceedsMaximumDepth represents an error message for a JSON request body that is nested too deeply.
+	ErrJSONExceedsMaximumDepth = "JSON request body must not exceed a nesting depth of %d"
+
+	// ErrJSONObjectExceedsMaximumKeys represents an error message for a JSON object that contains too many keys.
+	ErrJSONObjectExceedsMaximumKeys = "JSON objects must not contain more than %d keys"
+
+	// ErrJSONArrayExceedsMaximumLength represents an error message for a JSON array that contains too many elements.
+	ErrJSONArrayExceedsMaximumLength = "JSON arrays must not contain more than %d elements"
+
+	// ErrJSONStringExceedsMaximumLength represents an error message for a JSON string that is too long.
+	ErrJSONStringExceedsMaximumLength = "JSON strings must not exceed %d bytes"
+)
+
+const (
+	// ErrRequestBodyTooLarge represents an error message for a request body that exceeds the maximum allowed size.
+	ErrRequestBodyTooLarge = "Request body must not exceed %d bytes"
+
+	// ErrMediaTypeNotSupported represents an error message for a request body of a media type that is not accepted.
+	ErrMediaTypeNotSupported = "Unsupported media type '%s'"
+
+	// ErrCharsetNotSupported represents an error message for a request body in a charset that is not accepted.
+	ErrCharsetNotSupported = "Unsupported charset '%s'"
+)
+
+const (
+	// ErrFieldExceedsMaximumFiles represents an error message for a field that contains too many files.
+	ErrFieldExceedsMaximumFiles = "The '%s' field must not contain more than %d files"
+
+	// ErrFileExceedsMaximumSize represents an error message for a file that exceeds the maximum allowed size.
+	ErrFileExceedsMaximumSize = "Files in the '%s' field must not exceed %d bytes"
+
+	// ErrFilesExceedMaximumSize represents an error message for uploaded files that exceed the maximum allowed size together.
+	ErrFilesExceedMaximumSize = "Uploaded files must not exceed %d bytes in total"
+
+	// ErrFileExtensionNotAllowedInField represents an error message for a file whose extension is not accepted.
+	ErrFileExtensionNotAllowedInField = "Files in the '%s' field must have one of the allowed extensions"
+
+	// ErrFileTypeNotAllowedInField represents an error message for a file whose sniffed content type is not accepted.
+	ErrFileTypeNotAllowedInField = "Files in the '%s' field must be of one of the allowed types"
+
+	// ErrInvalidFilenameInField represents an error message for a file whose name contains characters that are not allowed.
+	ErrInvalidFilenameInField = "The '%s' field contains a file with an invalid name"
+)
+
+const (
+	// ErrFieldTypeMismatch represents an error message for a field whose value is not of the required type.
+	ErrFieldTypeMismatch = "The '%s' field must be of type %s"
+
+	// ErrTypeOrNull represents the expected type of a field that may also be null.
+	ErrTypeOrNull = "%s or null"
+)
+
+const (
+	// ErrFieldBelowMinimumItems represents an error message for an array field with fewer items than required.
+	ErrFieldBelowMinimumItems = "The '%s' field must contain at least %d items"
+
+	// ErrFieldExceedsMaximumItems represents an error message for an array field with more items than allowed.
+	ErrFieldExceedsMaximumItems = "The '%s' field must not contain more than %d items"
+
+	// ErrFieldContainsDuplicateItems represents an error message for an array field that contains the same value more than once.
+	ErrFieldContainsDuplicateItems = "The '%s' field must not contain duplicate items"
+)
+
+const (
+	// ErrInvalidImageInField represents an error message for a file that is not an image of a known format.
+	ErrInvalidImageInField = "The '%s' field contains a file that is not a valid image"
+
+	// ErrImageFormatNotAllowedInField represents an error message for an image whose format is not accepted.
+	ErrImageFormatNotAllowedInField = "Images in the '%s' field must be in one of the allowed formats"
+
+	// ErrImageExceedsMaximumWidth represents an error message for an image that is wider than allowed.
+	ErrImageExceedsMaximumWidth = "Images in the '%s' field must not be wider than %d pixels"
+
+	// ErrImageExceedsMaximumHeight represents an error message for an image that is taller than allowed.
+	ErrImageExceedsMaximumHeight = "Images in the '%s' field must not be taller than %d pixels"
+
+	// ErrImageExceedsMaximumPixels represents an error message for an image that has more pixels than allowed.
+	ErrImageExceedsMaximumPixels = "Images in the '%s' field must not exceed %d pixels"
+
+	// ErrImageAnimatedInField represents an error message for an animated image where only still images are accepted.
+	ErrImageAnimatedInField = "Images in the '%s' field must not be animated"
+)
+
+const (
+	// ErrFieldDoesNotMatchSchema represents an error message for a part of the request that violates its schema.
+	ErrFieldDoesNotMatchSchema = "The '%s' field %s"
+
+	// ErrNoOperationForRequest represents an error message for a request that matches no operation of the API specification.
+	ErrNoOperationForRequest = "No operation is defined for %s %s"
+
+	// ErrMethodNotAllowedForPath represents an error message for a request whose path has no operation for its method.
+	ErrMethodNotAllowedForPath = "Method %s is not allowed for %s"
+)
+
+// Error codes are stable, machine-readable identifiers of the built-in rule violations.
+// Unlike the error messages, they never change and are safe for clients to compare.
+const (
+	// CodeInvalidJSONBody is the error code for an invalid JSON request body.
+	CodeInvalidJSONBody = "INVALID_JSON_BODY"
+
+	// CodeInvalidXMLBody is the error code for an invalid XML request body.
+	CodeInvalidXMLBody = "INVALID_XML_BODY"
+
+	// CodeInvalidMsgPackBody is the error code for an invalid MessagePack request body.
+	CodeInvalidMsgPackBody = "INVALID_MSGPACK_BODY"
+
+	// CodeInvalidCBORBody is the error code for an invalid CBOR request body.
+	CodeInvalidCBORBody = "INVALID_CBOR_BODY"
+
+	// CodeInvalidYAMLBody is the error code for an invalid YAML request body.
+	CodeInvalidYAMLBody = "INVALID_YAML_BODY"
+
+	// CodeInvalidTOMLBody is the error code for an invalid TOML request body.
+	CodeInvalidTOMLBody = "INVALID_TOML_BODY"
+
+	// CodeInvalidBody is the error code for a request body of another content type that cannot be decoded.
+	CodeInvalidBody = "INVALID_BODY"
+
+	// CodeUnicodeNotAllowed is the error code for Unicode characters in a field that only allows ASCII.
+	CodeUnicodeNotAllowed = "UNICODE_NOT_ALLOWED"
+
+	// CodeNumberOnly is the error code for fields that must contain only numbers.
+	CodeNumberOnly = "NUMBER_ONLY"
+
+	// CodeMaxValueExceeded is the error code for a field that exceeds the maximum allowed value.
+	CodeMaxValueExceeded = "MAX_VALUE_EXCEEDED"
+
+	// CodeMaxDigitsExceeded is the error code for a field that exceeds the maximum allowed number of digits.
+	CodeMaxDigitsExceeded = "MAX_DIGITS_EXCEEDED"
+
+	// CodeMaxLengthExceeded is the error code for a field that exceeds the maximum allowed length.
+	CodeMaxLengthExceeded = "MAX_LENGTH_EXCEEDED"
+
+	// CodeDuplicateJSONKey is the error code for a JSON object that contains the same key more than once.
+	CodeDuplicateJSONKey = "DUPLICATE_JSON_KEY"
+
+	// CodeJSONMaxDepthExceeded is the error code for a JSON request body that is nested too deeply.
+	CodeJSONMaxDepthExceeded = "JSON_MAX_DEPTH_EXCEEDED"
+
+	// CodeJSONMaxKeysExceeded is the error code for a JSON object that contains too many keys.
+	CodeJSONMaxKeysExceeded = "JSON_MAX_KEYS_EXCEEDED"
+
+	// CodeJSONMaxArrayLengthExceeded is the error code for a JSON array that contains too many elements.
+	CodeJSONMaxArrayLengthExceeded = "JSON_MAX_ARRAY_LENGTH_EXCEEDED"
+
+	// CodeJSONMaxStringLengthExceeded is the error code for a JSON string that is too long.
+	CodeJSONMaxStringLengthExceeded = "JSON_MAX_STRING_LENGTH_EXCEEDED"
+
+	// CodeBodyTooLarge is the error code for a request body that exceeds the maximum allowed size.
+	CodeBodyTooLarge = "BODY_TOO_LARGE"
+
+	// CodeUnsupportedMediaType is the error code for a request body of a media type that is not accepted.
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+
+	// CodeUnsupportedCharset is the error code for a request body in a charset that is unknown or not accepted.
+	CodeUnsupportedCharset = "UNSUPPORTED_CHARSET"
+
+	// CodeTypeMismatch is the error code for a field whose value is not of the required type.
+	CodeTypeMismatch = "TYPE_MISMATCH"
+
+	// CodeTooManyFiles is the error code for a field that contains too many files.
+	CodeTooManyFiles = "TOO_MANY_FILES"
+
+	// CodeFileTooLarge is the error code for a file that exceeds the maximum allowed size.
+	CodeFileTooLarge = "FILE_TOO_LARGE"
+
+	// CodeFilesTooLarge is the error code for uploaded files that exceed the maximum allowed size together.
+	CodeFilesTooLarge = "FILES_TOO_LARGE"
+
+	// CodeFileExtensionNotAllowed is the error code for a file whose extension is not accepted.
+	CodeFileExtensionNotAllowed = "FILE_EXTENSION_NOT_ALLOWED"
+
+	// CodeFileTypeNotAllowed is the error code for a file whose sniffed content type is not accepted.
+	CodeFileTypeNotAllowed = "FILE_TYPE_NOT_ALLOWED"
+
+	// CodeInvalidFilename is the error code for a file whose name contains path separators, NUL bytes, or bidirectional control characters.
+	CodeInvalidFilename = "INVALID_FILENAME"
+
+	// CodeInvalidImage is the error code for a file that is not an image of a known format.
+	CodeInvalidImage = "INVALID_IMAGE"
+
+	// CodeImageFormatNotAllowed is the error code for an image whose format is not accepted.
+	CodeImageFormatNotAllowed = "IMAGE_FORMAT_NOT_ALLOWED"
+
+	// CodeImageWidthExceeded is the error code for an image that is wider than allowed.
+	CodeImageWidthExceeded = "IMAGE_WIDTH_EXCEEDED"
+
+	// CodeImageHeightExceeded is the error code for an image that is taller than allowed.
+	CodeImageHeightExceeded = "IMAGE_HEIGHT_EXCEEDED"
+
+	// CodeImagePixelsExceeded is the error code for an image that has more pixels than allowed.
+	CodeImagePixelsExceeded = "IMAGE_PIXELS_EXCEEDED"
+
+	// CodeImageAnimated is the error code for an animated image where only still images are accepted.
+	CodeImageAnimated = "IMAGE_ANIMATED"
+
+	// CodeTooFewItems is the error code for an array field with fewer items than required.
+	CodeTooFewItems = "TOO_FEW_ITEMS"
+
+	// CodeTooManyItems is the error code for an array field with more items than allowed.
+	CodeTooManyItems = "TOO_MANY_ITEMS"
+
+	// CodeDuplicateItems is the error code for an array field that contains the same value more than once.
+	CodeDuplicateItems = "DUPLICATE_ITEMS"
+
+	// CodeSchemaViolation is the error code for a part of the request that violates its schema in the API specification.
+	CodeSchemaViolation = "SCHEMA_VIOLATION"
+
+	// CodeOperationNotFound is the error code for a request that matches no operation of the API specification.
+	CodeOperationNotFound = "OPERATION_NOT_FOUND"
+
+	// CodeMethodNotAllowed is the error code for a request whose path has no operation for its method.
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+)
+
+// Media types of the request body formats that are supported in addition to the ones defined by Fiber.
+const (
+	// MIMEApplicationMsgPack is the media type of MessagePack request bodies.
+	// The unregistered application/x-msgpack and application/vnd.msgpack media types are accepted as well.
+	MIMEApplicationMsgPack = "application/msgpack"
+
+	// MIMEApplicationCBOR is the media type of CBOR request bodies.
+	MIMEApplicationCBOR = "application/cbor"
+
+	// MIMEApplicationYAML is the media type of YAML request bodies.
+	// The unregistered application/x-yaml and text/yaml media types are accepted as well.
+	MIMEApplicationYAML = "application/yaml"
+
+	// MIMEApplicationTOML is the media type of TOML request bodies.
+	MIMEApplicationTOML = "application/toml"
+
+	// MIMEApplicationNDJSON is the media type of NDJSON (JSON Lines) request bodies.
+	// The application/jsonl and application/x-jsonlines media types are accepted as well.
+	MIMEApplicationNDJSON = "application/x-ndjson"
+
+	mimeApplicationXMsgPack   = "application/x-msgpack"
+	mimeApplicationVndMsgPack = "application/vnd.msgpack"
+	mimeApplicationXYAML      = "application/x-yaml"
+	mimeTextYAML              = "text/yaml"
+	mimeApplicationJSONL      = "application/jsonl"
+	mimeApplicationXJSONLines = "application/x-jsonlines"
+)
+
 const (
 	// Define the range of numeric characters
 	numericStart = '0' + iota
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gofiber/fiber/v2"
+)
+
+// decodeContentEncoding decodes a request body with a gzip, deflate, or br Content-Encoding once and replaces the
+// request body with the result, removing the Content-Encoding header, so that c.Body() no longer decompresses the
+// body for every rule and the handler reads the decoded body as well.
+//
+// When maxSize is positive, at most maxSize+1 bytes are decoded and larger bodies are rejected with a 413 status,
+// so that a small body that expands hugely is never decompressed in full. Request bodies with an unknown
+// content coding are left unchanged.
+func decodeContentEncoding(c *fiber.Ctx, maxSize int) error {
+	req := c.Request()
+	encoding := strings.TrimSpace(string(req.Header.ContentEncoding()))
+	if encoding == "" {
+		return nil
+	}
+
+	// Content codings are listed in the order in which they were applied, so they are decoded in reverse.
+	codings := strings.Split(encoding, ",")
+	var r io.Reader = bytes.NewReader(req.Body())
+	for i := len(codings) - 1; i >= 0; i-- {
+		switch strings.ToLower(strings.TrimSpace(codings[i])) {
+		case "gzip", "x-gzip":
+			zr, err := gzip.NewReader(r)
+			if err != nil {
+				return errInvalidBody(err)
+			}
+			r = zr
+		case "deflate":
+			zr, err := zlib.NewReader(r)
+			if err != nil {
+				return errInvalidBody(err)
+			}
+			r = zr
+		case "br":
+			r = brotli.NewReader(r)
+		case "identity":
+		default:
+			return nil
+		}
+	}
+
+	if maxSize > 0 {
+		r = io.LimitReader(r, int64(maxSize)+1)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return errInvalidBody(err)
+	}
+	if maxSize > 0 && len(decoded) > maxSize {
+		return errBodyTooLarge(maxSize)
+	}
+
+	req.SetBodyRaw(decoded)
+	req.Header.Del(fiber.HeaderContentEncoding)
+	req.Header.SetContentLength(len(decoded))
+	return nil
+}
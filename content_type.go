@@ -0,0 +1,97 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// legacyTextScanKey is the context key under which the middleware enables Config.LegacyTextScan for the built-in rules.
+type legacyTextScanKey struct{}
+
+// contentTypesRestrictedKey is the context key under which Config.AllowedContentTypes marks the request
+// as restricted to the accepted media types, so that the built-in rules no longer scan other request bodies as text.
+type contentTypesRestrictedKey struct{}
+
+// requestContentTypeKey is the context key of the Content-Type header of the request while the lines of an NDJSON
+// request body are validated as JSON request bodies, so that the accepted media types are checked against the request.
+type requestContentTypeKey struct{}
+
+// allowedContentTypes is the Restrictor used for Config.AllowedContentTypes.
+type allowedContentTypes []string
+
+// Restrict implements the Restrictor interface for allowedContentTypes.
+func (a allowedContentTypes) Restrict(c *fiber.Ctx) error {
+	c.Locals(contentTypesRestrictedKey{}, true)
+	return restrictContentType(c, a)
+}
+
+// Name implements the NamedRestrictor interface for allowedContentTypes.
+func (a allowedContentTypes) Name() string {
+	return "AllowedContentTypes"
+}
+
+// requestLevel implements the requestRestrictor interface for allowedContentTypes.
+func (a allowedContentTypes) requestLevel() {}
+
+// alwaysEnforced implements the alwaysEnforcedRestrictor interface for allowedContentTypes.
+func (a allowedContentTypes) alwaysEnforced() {}
+
+// restrictContentType rejects the request with a 415 status when its body is not of one of the allowed media types.
+// Requests without a body are not checked, and an empty list allows every media type.
+func restrictContentType(c *fiber.Ctx, allowed []string) error {
+	if len(allowed) == 0 || len(c.Body()) == 0 {
+		return nil
+	}
+
+	mediaType := requestMediaType(c)
+	for _, pattern := range allowed {
+		if mediaTypeMatches(pattern, mediaType) {
+			return nil
+		}
+	}
+	return errUnsupportedMediaType(mediaType)
+}
+
+// requestMediaType returns the media type of the request body without its parameters,
+// or the Content-Type header as it is if it cannot be parsed.
+func requestMediaType(c *fiber.Ctx) string {
+	contentType := c.Get(fiber.HeaderContentType)
+	if original, ok := c.Locals(requestContentTypeKey{}).(string); ok {
+		contentType = original
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+// mediaTypeMatches reports whether a media type matches an accepted media type, which may be a media range
+// such as "text/*" or "*/*". Media types are compared case-insensitively and regardless of their parameters.
+func mediaTypeMatches(pattern, mediaType string) bool {
+	if parsed, _, err := mime.ParseMediaType(pattern); err == nil {
+		pattern = parsed
+	}
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	if typ, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(mediaType, typ+"/")
+	}
+	return false
+}
+
+// legacyTextScan reports whether request bodies of other content types are scanned as text. They are scanned
+// unless the accepted media types are restricted by the rule or by Config.AllowedContentTypes, in which case
+// the rule or the middleware must opt in with LegacyTextScan.
+func legacyTextScan(c *fiber.Ctx, rule bool, allowed []string) bool {
+	enabled, _ := c.Locals(legacyTextScanKey{}).(bool)
+	restricted, _ := c.Locals(contentTypesRestrictedKey{}).(bool)
+	return rule || enabled || (len(allowed) == 0 && !restricted)
+}
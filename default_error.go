@@ -4,12 +4,42 @@
 
 package validator
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+)
 
 // Error represents a validation error.
 type Error struct {
-	Status  int
+	// Status is the HTTP status code of the error response.
+	Status int
+
+	// Message is the human-readable error message.
 	Message string
+
+	// Code is the stable, machine-readable error code (e.g. "UNICODE_NOT_ALLOWED").
+	Code string
+
+	// Field is the name of the field that failed validation, if the error concerns a single field.
+	Field string
+
+	// Rule is the name of the rule that reported the error.
+	Rule string
+
+	// Params holds the parameters of the violated constraint (e.g. "max").
+	Params map[string]interface{}
+
+	// Err is the underlying error, such as a *FieldError or *BodyParseError wrapping one of the sentinel errors.
+	Err error
+
+	// value is the offending value, used by message templates.
+	value interface{}
+
+	// customMessage is true when the message comes from an ErrorOverride and must not be localized.
+	customMessage bool
 }
 
 // NewError creates a new Error instance.
@@ -25,32 +55,139 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying error, so that errors.Is and errors.As can inspect the violation.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 // DefaultErrorHandler is the default error handler function.
+// It also handles a *FieldError or *BodyParseError returned by a custom rule, and errors that wrap any of them.
 func DefaultErrorHandler(c *fiber.Ctx, err error) error {
-	if e, ok := err.(*Error); ok {
-		return restrictByContentType(c, jsonErrorHandler(e), xmlErrorHandler(e), defaultErrorHandler(e))
+	var (
+		e  *Error
+		fe *FieldError
+		be *BodyParseError
+	)
+	switch {
+	case errors.As(err, &e):
+	case errors.As(err, &fe):
+		e = &Error{Status: fiber.StatusBadRequest, Message: fe.Error(), Field: fe.Field, Err: fe}
+	case errors.As(err, &be):
+		e = &Error{Status: fiber.StatusBadRequest, Message: be.Error(), Err: be}
+	default:
+		return err
 	}
-	return err
+	switch format := bodyFormatOf(c); format {
+	case formatJSON, formatNDJSON:
+		return jsonErrorHandler(e)(c)
+	case formatXML:
+		return xmlErrorHandler(e)(c)
+	case formatOther:
+		return defaultErrorHandler(e)(c)
+	default:
+		codec, _ := codecOf(format)
+		return codecErrorHandler(e, codec)(c)
+	}
+}
+
+// jsonError is the JSON representation of an Error.
+// It is also used for the other formats that share the document model of JSON.
+type jsonError struct {
+	Error  string                 `json:"error" yaml:"error" toml:"error"`
+	Code   string                 `json:"code,omitempty" yaml:"code,omitempty" toml:"code,omitempty"`
+	Field  string                 `json:"field,omitempty" yaml:"field,omitempty" toml:"field,omitempty"`
+	Rule   string                 `json:"rule,omitempty" yaml:"rule,omitempty" toml:"rule,omitempty"`
+	Params map[string]interface{} `json:"params,omitempty" yaml:"params,omitempty" toml:"params,omitempty"`
 }
 
 // jsonErrorHandler formats the error as JSON.
 func jsonErrorHandler(e *Error) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
-		return c.Status(e.Status).JSON(fiber.Map{
-			"error": e.Message,
+		return c.Status(e.Status).JSON(jsonError{
+			Error:  e.Message,
+			Code:   e.Code,
+			Field:  e.Field,
+			Rule:   e.Rule,
+			Params: e.Params,
 		})
 	}
 }
 
-// xmlErrorHandler formats the error as XML.
+// codecErrorHandler formats the error with the codec of the request body format, using the fields of the JSON representation.
+func codecErrorHandler(e *Error, codec bodyCodec) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		data, err := codec.marshal(jsonError{
+			Error:  e.Message,
+			Code:   e.Code,
+			Field:  e.Field,
+			Rule:   e.Rule,
+			Params: e.Params,
+		})
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, codec.mediaType)
+		return c.Status(e.Status).Send(data)
+	}
+}
+
+// xmlError is the XML representation of an Error.
 type xmlError struct {
-	Error string `xml:"error"`
+	Error  string     `xml:"error"`
+	Code   string     `xml:"code,omitempty"`
+	Field  string     `xml:"field,omitempty"`
+	Rule   string     `xml:"rule,omitempty"`
+	Params *xmlParams `xml:"params,omitempty"`
+}
+
+// xmlParams holds the constraint parameters of an XML error.
+type xmlParams struct {
+	Param []xmlParam `xml:"param"`
+}
+
+// xmlParam is a single constraint parameter of an XML error. List values are written as repeated params.
+type xmlParam struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
 }
 
+// xmlErrorHandler formats the error as XML.
 func xmlErrorHandler(e *Error) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
-		return c.Status(e.Status).XML(xmlError{Error: e.Message})
+		return c.Status(e.Status).XML(xmlError{
+			Error:  e.Message,
+			Code:   e.Code,
+			Field:  e.Field,
+			Rule:   e.Rule,
+			Params: newXMLParams(e.Params),
+		})
+	}
+}
+
+// newXMLParams converts the error parameters into XML params sorted by name.
+func newXMLParams(params map[string]interface{}) *xmlParams {
+	if len(params) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := &xmlParams{}
+	for _, name := range names {
+		switch v := params[name].(type) {
+		case []string:
+			for _, s := range v {
+				out.Param = append(out.Param, xmlParam{Name: name, Value: s})
+			}
+		default:
+			out.Param = append(out.Param, xmlParam{Name: name, Value: fmt.Sprint(v)})
+		}
 	}
+	return out
 }
 
 // defaultErrorHandler sends the error as plain text.
@@ -47,6 +47,97 @@
 //
 // In the example above, a new instance of the validator middleware is created with a configuration that restricts the use of Unicode characters in the "name" and "email" fields of the request body.
 //
+// # Binding
+//
+// [validator.Bind] works like [validator.New], but once the request passed the rules it decodes the body into a struct
+// and stores it in the context, so the handler does not need to parse the body again. The built-in rules share a single decoded
+// document of the request body, and the struct is decoded once after they passed. Bodies rejected by the rules are never decoded into the struct,
+// and neither are bodies with violations reported in report-only mode, for which Body returns nil.
+//
+//	app.Post("/login", validator.Bind[Login](validator.Config{
+//		Rules: rules,
+//	}), func(c *fiber.Ctx) error {
+//		login := validator.Body[Login](c)
+//		// ...
+//	})
+//
+// # OpenAPI Validation
+//
+// [validator.LoadOpenAPI] loads an OpenAPI 3.0 or 3.1 specification and returns a [validator.RestrictOpenAPI] rule
+// that matches every request to its operation and validates the parameters and the JSON or form body against the declared schemas.
+// Violations use the SCHEMA_VIOLATION code with a JSON Pointer to the offending value in the field, such as "/body/address/zip".
+// Only local references are supported, so validation runs offline.
+//
+//	spec, err := validator.LoadOpenAPI(f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	app.Use(validator.New(validator.Config{
+//		Rules: []validator.Restrictor{spec},
+//	}))
+//
+// In the other direction, [validator.OpenAPISchema] generates the request body schema of a configuration, and
+// [validator.Registry.OpenAPIPaths] generates a paths object for the routes of a registry. Both return a [validator.OpenAPIFragment]
+// that is encoded as JSON or YAML and merged into the specification.
+//
+//	schema, err := validator.OpenAPISchema(cfg).YAML()
+//
+// # Configuration Files
+//
+// [validator.LoadConfig] reads a [validator.Config] from a YAML or JSON file, so that rules can be changed without rebuilding the application.
+// Rules are declared by type name with their parameters, and unknown rule types, unknown parameters, and invalid values
+// are reported with their line and column. Custom rules can be made available with [validator.RegisterRule].
+//
+//	maxBodySize: 4096
+//	rules:
+//	  - type: RestrictUnicode
+//	    fields: [username]
+//	  - type: RestrictStringLength
+//	    fields: [username]
+//	    maxLength: 32
+//
+// A [validator.Reloader] set as Config.Reloader swaps the rules of a running middleware atomically. Requests that are being
+// validated keep their rules, and a reload that fails keeps the previous rules and is reported through Reloader.OnError.
+//
+//	reloader := &validator.Reloader{OnError: func(err error) { log.Print(err) }}
+//	app.Use(validator.New(validator.Config{Reloader: reloader}))
+//
+//	go reloader.Watch(ctx, "rules.yaml", 5*time.Second)
+//
+// # Route Registry
+//
+// Instead of mounting a validator on every route, a [validator.Registry] declares rules by method and route pattern,
+// using the patterns the routes are registered with in Fiber. A single middleware applies the rules of the route that
+// matches the request. Declarations for unknown or overlapping routes are rejected when the app starts listening, or earlier
+// by calling [validator.Registry.Validate] once the routes are registered, and [validator.Registry.Routes] lists the effective
+// rules per route.
+//
+//	registry := validator.NewRegistry(validator.Config{MaxBodySize: 4 * 1024})
+//	registry.Add(fiber.MethodPost, "/login", validator.RestrictUnicode{Fields: []string{"username"}})
+//
+//	app.Use(registry.Handler(app))
+//	app.Post("/login", login)
+//
+//	if err := registry.Validate(app); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Validation Result
+//
+// Every validated request carries a [validator.Result] that [validator.ResultFrom] returns, and that is also stored
+// under Config.ContextKey when set. It tells whether the request passed, which rules ran, the outcome of every checked field,
+// and the validation duration. ResultFrom returns nil when the validator did not run. With Config.RecordValues, the result also
+// holds the sanitized values of the fields that passed every rule: long strings are truncated, and the values of fields
+// whose names suggest a secret, such as "password" or "token", are masked.
+//
+//	app.Post("/login", func(c *fiber.Ctx) error {
+//		result := validator.ResultFrom(c)
+//		if result == nil || !result.Passed {
+//			return fiber.ErrUnauthorized
+//		}
+//		// ...
+//	})
+//
 // # Configuration
 //
 // The validator middleware accepts a [validator.Config] struct for configuration. The available options are:
@@ -54,6 +145,54 @@
 //   - Rules: A slice of [validator.Restrictor] implementations that define the validation rules to be applied.
 //   - Next: An optional function that determines whether to skip the validation middleware for a given request. If the function returns true, the middleware will be skipped.
 //   - ErrorHandler: An optional custom error handler function that handles the error response. If not provided, the default error handler will be used.
+//   - RecordValues: An optional flag that records the sanitized values of the fields that passed every rule in the [validator.Result] of the request. Long strings are truncated, and the values of fields whose names suggest a secret are masked.
+//   - MaxBodySize: An optional maximum request body size in bytes. Larger requests, including requests whose Content-Length header already exceeds the limit, are rejected with a 413 status before any rule parses the body. Compressed request bodies are decoded once, and only up to the limit. The built-in rules also accept their own MaxBodySize for per-route limits.
+//   - AllowedContentTypes: An optional list of accepted media types or media ranges. Request bodies of other media types are rejected with a 415 status before any rule runs, and the built-in rules reject request bodies that they cannot decode instead of scanning them as text. The built-in rules also accept their own AllowedContentTypes.
+//   - LegacyTextScan: Keeps scanning request bodies of other content types, such as text/plain, for the fields as text when AllowedContentTypes is set, instead of rejecting them with a 415 status. The built-in rules also accept their own LegacyTextScan.
+//   - Transcode: An optional Transcode that transcodes request bodies in another charset, such as ISO-8859-1, Shift_JIS, or UTF-16, to UTF-8 before the rules run, and rejects unknown or disallowed charsets with a 415 status.
+//   - NDJSONMaxErrors: An optional number of invalid lines of an NDJSON request body that a rule collects before the request is rejected. By default, the request is rejected at the first invalid line.
+//   - ReportOnly: An optional flag that runs every rule but never rejects the request. Violations are stored in the [validator.Result] of the request and passed to OnReport, and the next handler is always called. Individual built-in rules can also set their own ReportOnly flag. MaxBodySize, JSONLimits, AllowedContentTypes, and Transcode are always enforced.
+//   - EnforcePercentage: An optional percentage (0-100) of requests for which report-only violations are enforced anyway, for gradual rollouts.
+//   - OnReport: An optional callback that receives every violation that is reported instead of enforced.
+//   - OnRuleEvaluated, OnSuccess, OnFailure: Optional observability hooks. OnRuleEvaluated receives a [validator.Evaluation] with the rule name, fields, duration, and violation of every rule; OnSuccess receives the total validation duration; OnFailure receives the evaluation of the rule that rejected the request.
+//   - Localizer: An optional [validator.Localizer] that translates the messages of rejected requests by error code. English, Indonesian, Japanese, and German are built in, and applications can register or override translations.
+//   - Language: An optional function that returns the preferred language of the request. By default, the Accept-Language header is used.
+//   - JSONLimits: An optional [validator.JSONLimits] that token-scans JSON request bodies before any rule runs, rejecting duplicate keys and documents that exceed the configured nesting depth, key count, array length, or string length.
+//   - Reloader: An optional [validator.Reloader] that swaps the rules of the running middleware at runtime.
+//
+// # Observability
+//
+// The [validator.SlogHook] adapter logs every rule evaluation with log/slog, and [validator.Metrics] is an in-process
+// registry of counters and duration histograms that can be served in the Prometheus text format:
+//
+//	metrics := validator.NewMetrics()
+//
+//	app.Get("/metrics", metrics.Handler())
+//
+//	app.Use(validator.New(validator.Config{
+//		Rules:           rules,
+//		OnRuleEvaluated: metrics.OnRuleEvaluated,
+//	}))
+//
+// Rules are identified by their Go type name unless they implement [validator.NamedRestrictor].
+//
+// # Localization
+//
+// Error messages can be translated with a [validator.Localizer], which is built on golang.org/x/text/message.
+// The language is matched against the Accept-Language header unless Config.Language is provided:
+//
+//	localizer := validator.NewLocalizer()
+//	localizer.Register(language.French, validator.CodeUnicodeNotAllowed, "Les caractères Unicode ne sont pas autorisés dans le champ '%[1]s'")
+//
+//	app.Use(validator.New(validator.Config{
+//		Rules:     rules,
+//		Localizer: localizer,
+//	}))
+//
+// Translations receive the field name as the first argument, the limit of the violated constraint as the second argument,
+// and the rejected media type, charset, or type as the third argument.
+// The line number of NDJSON errors is added with the translation registered under validator.ErrInvalidNDJSONLine,
+// which receives the line number and the translated message.
 //
 // # Custom Validation Rules
 //
@@ -81,14 +220,148 @@
 //		return nil
 //	}
 //
+// # Request Body Formats
+//
+// The built-in rules decode JSON, MessagePack (application/msgpack), CBOR (application/cbor), YAML (application/yaml), and TOML (application/toml)
+// request bodies into the same document model, where numbers of any encoding are compared as float64, so the rules apply to the fields
+// of all these formats alike. Keys that are not strings are compared by their string form, and a body with two keys of the
+// same string form, such as 1 and "1", is rejected as invalid. The aliases of a YAML request body may add at most 10,000 nodes when they are expanded, so that small
+// documents cannot expand into huge ones.
+//
+// The Content-Type header is parsed as defined in RFC 9110, so media types match regardless of case and parameters, and
+// media types with a structured syntax suffix, such as application/vnd.api+json or application/soap+xml, are decoded
+// in the format of their suffix. [validator.RegisterMediaType] makes further media types available with their own decoder.
+//
+//	validator.RegisterMediaType("application/x-protobuf+json", protojsonUnmarshal)
+//
+// NDJSON (JSON Lines) request bodies (application/x-ndjson) are validated line by line: every non-empty line is validated
+// with the rules as a separate JSON request body, while MaxBodySize applies to the whole request. The error of the first
+// invalid line carries its number in the message and in the "line" param, and wraps a [validator.LineError] for every
+// collected line. With Config.NDJSONMaxErrors, up to that many invalid lines are collected and listed in the "errors" param.
+// XML request bodies are decoded into the fields of the rule.
+//
+// Request bodies of other content types, such as application/octet-stream, form request bodies, or request bodies without
+// a Content-Type header, are searched for the field names as text, which can both miss and misreport values.
+// Config.AllowedContentTypes, and the AllowedContentTypes option of a rule, restrict the accepted media types, and
+// request bodies of other media types are rejected with a 415 status and the UNSUPPORTED_MEDIA_TYPE code; media ranges
+// such as "text/*" are supported. Once the media types are restricted, the built-in rules also reject accepted request
+// bodies that they cannot decode with a 415 status, unless Config.LegacyTextScan, or the LegacyTextScan option of a rule,
+// keeps the text scan.
+//
+//	app.Use(validator.New(validator.Config{
+//		AllowedContentTypes: []string{"application/json", "application/xml"},
+//		Rules:               rules,
+//	}))
+//
+// Config.Transcode transcodes request bodies in another charset to UTF-8 before the rules run, so that their characters
+// and lengths are judged correctly. The charset is taken from the charset parameter of the Content-Type header or, for XML,
+// from the byte order mark or the XML declaration, and is updated along with the body. NDJSON request bodies are split into
+// lines after they are transcoded. Request bodies in an unknown or disallowed charset are rejected with a 415 status and
+// the UNSUPPORTED_CHARSET code, and Config.MaxBodySize also limits the transcoded body.
+//
+//	app.Use(validator.New(validator.Config{
+//		Transcode: &validator.Transcode{AllowedCharsets: []string{"ISO-8859-1", "Shift_JIS", "UTF-16"}},
+//		Rules:     rules,
+//	}))
+//
+// # Value Types
+//
+// RestrictUnicode and RestrictStringLength only check string values, so by default a client can send a number, an array,
+// or an object in their place without being checked. [validator.RestrictType] requires fields to be of a JSON type,
+// optionally accepting null, and the StrictTypes option of RestrictUnicode and RestrictStringLength rejects values
+// that are not strings or null. Both report a TYPE_MISMATCH error with the "type" and "actual" params, and the "nullable"
+// param when null is accepted as well.
+//
+//	validator.RestrictType{Fields: []string{"age"}, Type: validator.TypeInteger},
+//	validator.RestrictType{Fields: []string{"nickname"}, Type: validator.TypeString, Nullable: true},
+//	validator.RestrictUnicode{Fields: []string{"name"}, StrictTypes: true},
+//
+// In configuration files, the type of RestrictType is declared as jsonType. XML request bodies and request bodies
+// scanned as text only contain strings, so their fields are not type-checked.
+//
+// # Arrays
+//
+// [validator.RestrictArray] checks the number of items of array fields and the uniqueness of their items, and applies its
+// element rules to every item. The element rules are the other rules, such as RestrictUnicode and RestrictStringLength,
+// which validate every item as a field named after the array and the index of the item, so their Fields can be left empty.
+// The built-in element rules check the items of the decoded request body directly, and the items are not listed as
+// fields of the [validator.Result].
+//
+//	validator.RestrictArray{
+//		Fields:      []string{"tags"},
+//		MinItems:    &minTags,
+//		MaxItems:    &maxTags,
+//		UniqueItems: true,
+//		Each: []validator.Restrictor{
+//			validator.RestrictUnicode{StrictTypes: true},
+//			validator.RestrictStringLength{MaxLength: &maxTagLength},
+//		},
+//	},
+//
+// Item counts and duplicates are reported as TOO_FEW_ITEMS, TOO_MANY_ITEMS, and DUPLICATE_ITEMS errors. Errors of the element
+// rules name the item as their field, such as "tags[2]", and report its index in the "index" param. Values that are not
+// arrays are rejected with a TYPE_MISMATCH error. In configuration files, the element rules are declared under each.
+//
+// # File Uploads
+//
+// [validator.RestrictFiles] checks the files of multipart/form-data request bodies: the number of files per field, the size
+// of every file and of all files together, the file name extensions, and the content types of the files. Content types are
+// sniffed from the content with the Signatures of the rule and net/http.DetectContentType, so a client cannot pass a file off
+// as another type by declaring it. File names containing path separators, NUL bytes, or Unicode bidirectional control
+// characters are always rejected.
+//
+//	validator.RestrictFiles{
+//		Fields:            []string{"avatar"},
+//		MaxFiles:          1,
+//		MaxFileSize:       2 << 20,
+//		AllowedExtensions: []string{".png", ".jpg", ".heic"},
+//		AllowedFileTypes:  []string{"image/png", "image/jpeg", "image/heic"},
+//		Signatures: []validator.FileSignature{
+//			{ContentType: "image/heic", Offset: 4, Magic: []byte("ftypheic")},
+//		},
+//	}
+//
+// [validator.RestrictImage] checks uploaded images by their format and pixel dimensions, and rejects animated GIF and PNG images
+// unless AllowAnimated is set. Only the image headers are decoded with image.DecodeConfig, so images that would decompress
+// into huge bitmaps are rejected before any pixel data is read. Every error names the exceeded limit in its code, such as
+// IMAGE_WIDTH_EXCEEDED or IMAGE_ANIMATED.
+//
+//	validator.RestrictImage{
+//		Fields:         []string{"avatar"},
+//		AllowedFormats: []string{"png", "jpeg", "gif"},
+//		MaxWidth:       4096,
+//		MaxHeight:      4096,
+//	}
+//
+// Since the other built-in rules only scan multipart/form-data request bodies as text, or reject them when
+// AllowedContentTypes is set, upload routes are usually validated with RestrictFiles and RestrictImage alone.
+//
 // # Error Handling
 //
-// The validator middleware provides a default error handler that formats the error response based on the content type of the request. It supports JSON, XML, and plain text formats.
+// The validator middleware provides a default error handler that formats the error response based on the content type of the request. It supports JSON, XML, MessagePack, CBOR, YAML, TOML, and plain text formats.
 //
-//   - For JSON requests, the error response is formatted as {"error": "Error message"}.
-//   - For XML requests, the error response is formatted as <xmlError><error>Error message</error></xmlError>.
+//   - For JSON requests, the error response is formatted as {"error": "Error message", "code": "MAX_LENGTH_EXCEEDED", "field": "name", "rule": "RestrictStringLength", "params": {"max": 50}}.
+//   - For XML requests, the error response is formatted as <xmlError><error>Error message</error><code>MAX_LENGTH_EXCEEDED</code><field>name</field><rule>RestrictStringLength</rule><params><param name="max">50</param></params></xmlError>.
+//   - For MessagePack, CBOR, YAML, and TOML requests, the error response is a map with the same entries as the JSON error response, encoded in the format of the request.
 //   - For other content types, the error response is sent as plain text.
 //
+// The code is a stable, machine-readable identifier of the violation (see the Code* constants), so clients do not need to parse
+// the human-readable message. The field, rule, and params entries are omitted when they do not apply.
+//
+// The message and status code of the built-in rules can be customized without writing an error handler.
+// The Message and Status fields apply to the whole rule, and FieldOverrides applies to individual fields:
+//
+//	validator.RestrictNumberOnly{
+//		Fields:    []string{"phone"},
+//		MaxDigits: &maxDigits,
+//		Status:    fiber.StatusUnprocessableEntity,
+//		FieldOverrides: map[string]validator.ErrorOverride{
+//			"phone": {Message: "Phone number should be at most {max} digits"},
+//		},
+//	}
+//
+// The placeholders {field}, {value}, {max}, and {limit} are replaced with the details of the violation. Custom messages are not translated by the Localizer.
+//
 // You can customize the error handling behavior by providing a custom error handler function in the ErrorHandler field of the [validator.Config] struct. The custom error handler should have the following signature:
 //
 //	func(c *fiber.Ctx, err error) error
@@ -96,7 +369,8 @@
 // Example custom error handler:
 //
 //	func customErrorHandler(c *fiber.Ctx, err error) error {
-//		if e, ok := err.(*validator.Error); ok {
+//		var e *validator.Error
+//		if errors.As(err, &e) {
 //			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 //				"custom_error": e.Message,
 //			})
@@ -105,4 +379,13 @@
 //	}
 //
 // In this example, the custom error handler checks if the error is of type [*validator.Error] and returns a JSON response with a custom error format.
+//
+// The errors of the built-in rules wrap sentinel errors such as [validator.ErrUnicodeNotAllowed], [validator.ErrMaxLengthExceeded],
+// [validator.ErrNotNumeric], and [validator.ErrInvalidBody], so the failing rule can be identified with errors.Is instead of comparing messages.
+// Field violations are wrapped in a [*validator.FieldError], and bodies that cannot be decoded in a [*validator.BodyParseError] that carries the decode error:
+//
+//	var fe *validator.FieldError
+//	if errors.Is(err, validator.ErrMaxLengthExceeded) && errors.As(err, &fe) {
+//		log.Printf("field %s is too long", fe.Field)
+//	}
 package validator
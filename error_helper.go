@@ -0,0 +1,295 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newRuleError creates a new Error for a violation of a built-in rule.
+// The error wraps the sentinel of its code, through a FieldError for every failing field.
+func newRuleError(status int, code, field string, params map[string]interface{}, message string) *Error {
+	sentinel := codeSentinels[code]
+	err := sentinel
+	if fields, ok := params["fields"].([]string); ok {
+		errs := make([]error, len(fields))
+		for i, f := range fields {
+			errs[i] = &FieldError{Field: f, Err: sentinel}
+		}
+		err = errors.Join(errs...)
+	} else if field != "" {
+		err = &FieldError{Field: field, Err: sentinel}
+	}
+
+	return &Error{
+		Status:  status,
+		Message: message,
+		Code:    code,
+		Field:   field,
+		Params:  params,
+		Err:     err,
+	}
+}
+
+// withValue records the offending value for message templates.
+func (e *Error) withValue(value interface{}) *Error {
+	e.value = value
+	return e
+}
+
+// errInvalidJSONBody returns the error for a JSON request body that cannot be decoded.
+func errInvalidJSONBody(cause error) *Error {
+	e := newRuleError(fiber.StatusBadRequest, CodeInvalidJSONBody, "", nil, ErrInvalidJSONBody)
+	e.Err = &BodyParseError{Format: "JSON", Err: cause}
+	return e
+}
+
+// errInvalidXMLBody returns the error for an XML request body that cannot be decoded.
+func errInvalidXMLBody(cause error) *Error {
+	e := newRuleError(fiber.StatusBadRequest, CodeInvalidXMLBody, "", nil, ErrInvalidXMLBody)
+	e.Err = &BodyParseError{Format: "XML", Err: cause}
+	return e
+}
+
+// errInvalidMsgPackBody returns the error for a MessagePack request body that cannot be decoded.
+func errInvalidMsgPackBody(cause error) *Error {
+	e := newRuleError(fiber.StatusBadRequest, CodeInvalidMsgPackBody, "", nil, ErrInvalidMsgPackBody)
+	e.Err = &BodyParseError{Format: "MessagePack", Err: cause}
+	return e
+}
+
+// errInvalidCBORBody returns the error for a CBOR request body that cannot be decoded.
+func errInvalidCBORBody(cause error) *Error {
+	e := newRuleError(fiber.StatusBadRequest, CodeInvalidCBORBody, "", nil, ErrInvalidCBORBody)
+	e.Err = &BodyParseError{Format: "CBOR", Err: cause}
+	return e
+}
+
+// errInvalidYAMLBody returns the error for a YAML request body that cannot be decoded.
+func errInvalidYAMLBody(cause error) *Error {
+	e := newRuleError(fiber.StatusBadRequest, CodeInvalidYAMLBody, "", nil, ErrInvalidYAMLBody)
+	e.Err = &BodyParseError{Format: "YAML", Err: cause}
+	return e
+}
+
+// errInvalidTOMLBody returns the error for a TOML request body that cannot be decoded.
+func errInvalidTOMLBody(cause error) *Error {
+	e := newRuleError(fiber.StatusBadRequest, CodeInvalidTOMLBody, "", nil, ErrInvalidTOMLBody)
+	e.Err = &BodyParseError{Format: "TOML", Err: cause}
+	return e
+}
+
+// errInvalidBody returns the error for a request body of another content type that cannot be decoded.
+func errInvalidBody(cause error) *Error {
+	e := newRuleError(fiber.StatusBadRequest, CodeInvalidBody, "", nil, ErrInvalidRequestBody)
+	e.Err = &BodyParseError{Format: "request", Err: cause}
+	return e
+}
+
+// errUnicodeNotAllowed returns the error for a field that contains Unicode characters.
+func errUnicodeNotAllowed(field, value string) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeUnicodeNotAllowed, field, nil,
+		fmt.Sprintf(ErrUnicodeNotAllowedInField, field)).withValue(value)
+}
+
+// errNumberOnly returns the error for fields that do not contain only numbers.
+// The Field is only set when a single field is invalid; all invalid fields are listed in the "fields" param.
+func errNumberOnly(fields []string) *Error {
+	var field string
+	if len(fields) == 1 {
+		field = fields[0]
+	}
+	return newRuleError(fiber.StatusBadRequest, CodeNumberOnly, field, map[string]interface{}{"fields": fields},
+		fmt.Sprintf(ErrFieldMustContainNumbersOnly, strings.Join(fields, "', '")))
+}
+
+// errMaxValueExceeded returns the error for a field that exceeds the maximum allowed value.
+func errMaxValueExceeded(field string, value interface{}, max int) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeMaxValueExceeded, field, map[string]interface{}{"max": max},
+		fmt.Sprintf(ErrFieldExceedsMaximumValue, field, max)).withValue(value)
+}
+
+// errMaxDigitsExceeded returns the error for a field that exceeds the maximum allowed number of digits.
+func errMaxDigitsExceeded(field string, value interface{}, max int) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeMaxDigitsExceeded, field, map[string]interface{}{"max": max},
+		fmt.Sprintf(ErrFieldExceedsMaximumDigits, field, max)).withValue(value)
+}
+
+// errMaxLengthExceeded returns the error for a field that exceeds the maximum allowed length.
+func errMaxLengthExceeded(field string, value string, max int) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeMaxLengthExceeded, field, map[string]interface{}{"max": max},
+		fmt.Sprintf(ErrFieldExceedsMaximumLength, field, max)).withValue(value)
+}
+
+// errFieldsExceedMaximumLength returns the error for several fields that exceed the maximum allowed length.
+func errFieldsExceedMaximumLength(fields []string) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeMaxLengthExceeded, "", map[string]interface{}{"fields": fields},
+		fmt.Sprintf(ErrFieldsExceedMaximumLength, strings.Join(fields, "', '")))
+}
+
+// errBodyTooLarge returns the error for a request body that exceeds the maximum allowed size.
+func errBodyTooLarge(max int) *Error {
+	return newRuleError(fiber.StatusRequestEntityTooLarge, CodeBodyTooLarge, "", map[string]interface{}{"max": max},
+		fmt.Sprintf(ErrRequestBodyTooLarge, max))
+}
+
+// errDuplicateJSONKey returns the error for a JSON object that contains the same key more than once.
+func errDuplicateJSONKey(key string) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeDuplicateJSONKey, key, nil,
+		fmt.Sprintf(ErrDuplicateJSONKey, key))
+}
+
+// errJSONLimitExceeded returns the error for a JSON request body that exceeds one of the JSONLimits.
+func errJSONLimitExceeded(code, format string, max int) *Error {
+	return newRuleError(fiber.StatusBadRequest, code, "", map[string]interface{}{"max": max},
+		fmt.Sprintf(format, max))
+}
+
+// errUnsupportedMediaType returns the error for a request body of a media type that is not accepted.
+func errUnsupportedMediaType(mediaType string) *Error {
+	return newRuleError(fiber.StatusUnsupportedMediaType, CodeUnsupportedMediaType, "", map[string]interface{}{"mediaType": mediaType},
+		fmt.Sprintf(ErrMediaTypeNotSupported, mediaType))
+}
+
+// errUnsupportedCharset returns the error for a request body in a charset that is unknown or not accepted.
+func errUnsupportedCharset(charset string) *Error {
+	return newRuleError(fiber.StatusUnsupportedMediaType, CodeUnsupportedCharset, "", map[string]interface{}{"charset": charset},
+		fmt.Sprintf(ErrCharsetNotSupported, charset))
+}
+
+// errTypeMismatch returns the error for a field whose value is not of the required type.
+func errTypeMismatch(field string, expected JSONType, nullable bool, value interface{}) *Error {
+	typ := string(expected)
+	params := map[string]interface{}{"type": string(expected), "actual": string(jsonTypeOf(value))}
+	if nullable {
+		typ = fmt.Sprintf(ErrTypeOrNull, typ)
+		params["nullable"] = true
+	}
+	return newRuleError(fiber.StatusBadRequest, CodeTypeMismatch, field, params,
+		fmt.Sprintf(ErrFieldTypeMismatch, field, typ)).withValue(value)
+}
+
+// errTooManyFiles returns the error for a field that contains more files than allowed.
+func errTooManyFiles(field string, max int) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeTooManyFiles, field, map[string]interface{}{"max": max},
+		fmt.Sprintf(ErrFieldExceedsMaximumFiles, field, max))
+}
+
+// errFileTooLarge returns the error for a file that exceeds the maximum allowed size.
+func errFileTooLarge(field, filename string, max int) *Error {
+	return newRuleError(fiber.StatusRequestEntityTooLarge, CodeFileTooLarge, field, map[string]interface{}{"max": max, "filename": filename},
+		fmt.Sprintf(ErrFileExceedsMaximumSize, field, max)).withValue(filename)
+}
+
+// errFilesTooLarge returns the error for uploaded files that exceed the maximum allowed size together.
+func errFilesTooLarge(max int) *Error {
+	return newRuleError(fiber.StatusRequestEntityTooLarge, CodeFilesTooLarge, "", map[string]interface{}{"max": max},
+		fmt.Sprintf(ErrFilesExceedMaximumSize, max))
+}
+
+// errFileExtensionNotAllowed returns the error for a file whose extension is not accepted.
+func errFileExtensionNotAllowed(field, filename string) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeFileExtensionNotAllowed, field, map[string]interface{}{"filename": filename},
+		fmt.Sprintf(ErrFileExtensionNotAllowedInField, field)).withValue(filename)
+}
+
+// errFileTypeNotAllowed returns the error for a file whose sniffed content type is not accepted.
+func errFileTypeNotAllowed(field, filename, contentType string) *Error {
+	return newRuleError(fiber.StatusUnsupportedMediaType, CodeFileTypeNotAllowed, field,
+		map[string]interface{}{"filename": filename, "contentType": contentType},
+		fmt.Sprintf(ErrFileTypeNotAllowedInField, field)).withValue(filename)
+}
+
+// errInvalidFilename returns the error for a file whose name contains characters that are not allowed.
+// The name is not echoed, since it may contain control characters.
+func errInvalidFilename(field string) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeInvalidFilename, field, nil,
+		fmt.Sprintf(ErrInvalidFilenameInField, field))
+}
+
+// errInvalidImage returns the error for a file that is not an image of a known format.
+func errInvalidImage(field, filename string) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeInvalidImage, field, map[string]interface{}{"filename": filename},
+		fmt.Sprintf(ErrInvalidImageInField, field)).withValue(filename)
+}
+
+// errImageFormatNotAllowed returns the error for an image whose format is not accepted.
+func errImageFormatNotAllowed(field, filename, format string) *Error {
+	return newRuleError(fiber.StatusUnsupportedMediaType, CodeImageFormatNotAllowed, field,
+		map[string]interface{}{"filename": filename, "format": format},
+		fmt.Sprintf(ErrImageFormatNotAllowedInField, field)).withValue(filename)
+}
+
+// errImageWidthExceeded returns the error for an image that is wider than allowed.
+func errImageWidthExceeded(field, filename string, width, max int) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeImageWidthExceeded, field,
+		map[string]interface{}{"max": max, "width": width, "filename": filename},
+		fmt.Sprintf(ErrImageExceedsMaximumWidth, field, max)).withValue(filename)
+}
+
+// errImageHeightExceeded returns the error for an image that is taller than allowed.
+func errImageHeightExceeded(field, filename string, height, max int) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeImageHeightExceeded, field,
+		map[string]interface{}{"max": max, "height": height, "filename": filename},
+		fmt.Sprintf(ErrImageExceedsMaximumHeight, field, max)).withValue(filename)
+}
+
+// errImagePixelsExceeded returns the error for an image that has more pixels than allowed.
+func errImagePixelsExceeded(field, filename string, pixels int64, max int) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeImagePixelsExceeded, field,
+		map[string]interface{}{"max": max, "pixels": pixels, "filename": filename},
+		fmt.Sprintf(ErrImageExceedsMaximumPixels, field, max)).withValue(filename)
+}
+
+// errImageAnimated returns the error for an animated image where only still images are accepted.
+func errImageAnimated(field, filename string) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeImageAnimated, field, map[string]interface{}{"filename": filename},
+		fmt.Sprintf(ErrImageAnimatedInField, field)).withValue(filename)
+}
+
+// errTooFewItems returns the error for an array field with fewer items than required.
+func errTooFewItems(field string, min int, items []interface{}) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeTooFewItems, field, map[string]interface{}{"min": min, "count": len(items)},
+		fmt.Sprintf(ErrFieldBelowMinimumItems, field, min)).withValue(items)
+}
+
+// errTooManyItems returns the error for an array field with more items than allowed.
+func errTooManyItems(field string, max int, items []interface{}) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeTooManyItems, field, map[string]interface{}{"max": max, "count": len(items)},
+		fmt.Sprintf(ErrFieldExceedsMaximumItems, field, max)).withValue(items)
+}
+
+// errDuplicateItems returns the error for an array field whose item at the index equals an earlier item.
+func errDuplicateItems(field string, index int, item interface{}) *Error {
+	return newRuleError(fiber.StatusBadRequest, CodeDuplicateItems, field, map[string]interface{}{"index": index},
+		fmt.Sprintf(ErrFieldContainsDuplicateItems, field)).withValue(item)
+}
+
+// errSchemaViolation returns the error for a part of the request, identified by a JSON Pointer, that violates its schema.
+// The keyword is the schema keyword that was violated, and limit is its value for keywords with a limit.
+func errSchemaViolation(pointer, reason, keyword string, limit, value interface{}) *Error {
+	params := map[string]interface{}{"keyword": keyword}
+	if limit != nil {
+		params["limit"] = limit
+	}
+	return newRuleError(fiber.StatusBadRequest, CodeSchemaViolation, pointer, params,
+		fmt.Sprintf(ErrFieldDoesNotMatchSchema, pointer, reason)).withValue(value)
+}
+
+// errOperationNotFound returns the error for a request that matches no operation of the API specification.
+func errOperationNotFound(method, path string) *Error {
+	return newRuleError(fiber.StatusNotFound, CodeOperationNotFound, "", nil,
+		fmt.Sprintf(ErrNoOperationForRequest, method, path))
+}
+
+// errMethodNotAllowed returns the error for a request whose path has no operation for its method.
+func errMethodNotAllowed(method, path string) *Error {
+	return newRuleError(fiber.StatusMethodNotAllowed, CodeMethodNotAllowed, "", nil,
+		fmt.Sprintf(ErrMethodNotAllowedForPath, method, path))
+}
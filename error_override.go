@@ -0,0 +1,102 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrorOverride customizes the message and status code of the errors reported by a rule.
+//
+// The message is a template in which the placeholders {field}, {value}, {max}, and {limit} (an alias of {max})
+// are replaced with the details of the violation, for example "Phone number should be at most {max} digits".
+// Every other constraint parameter of the error is available under its own name, and unknown placeholders are left as they are.
+type ErrorOverride struct {
+	// Message is the template of the error message (optional).
+	Message string
+
+	// Status is the HTTP status code of the error response (optional).
+	Status int
+}
+
+// overrideError applies the rule-wide override and the override of the failing field to a violation.
+// For a violation of several fields, the overrides of the fields are consulted in the order of the fields,
+// and the first message and the first status found apply.
+// Errors that do not concern a field, such as an invalid request body, are returned unchanged.
+func overrideError(err error, rule ErrorOverride, fields map[string]ErrorOverride) error {
+	var e *Error
+	if !errors.As(err, &e) || (e.Field == "" && e.Params["fields"] == nil) {
+		return err
+	}
+
+	failing := []string{e.Field}
+	if names, ok := e.Params["fields"].([]string); ok && e.Field == "" {
+		failing = names
+	}
+
+	var field ErrorOverride
+	for _, name := range failing {
+		f := fields[name]
+		if field.Message == "" {
+			field.Message = f.Message
+		}
+		if field.Status == 0 {
+			field.Status = f.Status
+		}
+	}
+
+	override := rule
+	if field.Message != "" {
+		override.Message = field.Message
+	}
+	if field.Status != 0 {
+		override.Status = field.Status
+	}
+
+	if override.Status != 0 {
+		e.Status = override.Status
+	}
+	if override.Message != "" {
+		e.Message = renderMessage(override.Message, e)
+		e.customMessage = true
+	}
+	return err
+}
+
+// renderMessage replaces the {placeholder} names in a message template with the details of the error.
+func renderMessage(template string, e *Error) string {
+	names := make([]string, 0, len(e.Params))
+	for name := range e.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	field := e.Field
+	if fields, ok := e.Params["fields"].([]string); ok && field == "" {
+		field = strings.Join(fields, ", ")
+	}
+
+	oldnew := []string{"{field}", field}
+	if e.value != nil {
+		oldnew = append(oldnew, "{value}", fmt.Sprint(e.value))
+	}
+	if max, ok := e.Params["max"]; ok {
+		oldnew = append(oldnew, "{limit}", fmt.Sprint(max))
+	}
+	for _, name := range names {
+		var v string
+		switch p := e.Params[name].(type) {
+		case []string:
+			v = strings.Join(p, ", ")
+		default:
+			v = fmt.Sprint(p)
+		}
+		oldnew = append(oldnew, "{"+name+"}", v)
+	}
+	return strings.NewReplacer(oldnew...).Replace(template)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors identify the kind of violation reported by the built-in rules.
+// They are wrapped by the *Error returned from the rules and can be checked with errors.Is:
+//
+//	if errors.Is(err, validator.ErrUnicodeNotAllowed) {
+//		// ...
+//	}
+var (
+	// ErrInvalidBody is reported when the request body cannot be decoded.
+	ErrInvalidBody = errors.New("validator: invalid request body")
+
+	// ErrUnicodeNotAllowed is reported when a field contains Unicode characters.
+	ErrUnicodeNotAllowed = errors.New("validator: unicode characters not allowed")
+
+	// ErrNotNumeric is reported when a field does not contain only numbers.
+	ErrNotNumeric = errors.New("validator: value is not numeric")
+
+	// ErrMaxValueExceeded is reported when a field exceeds the maximum allowed value.
+	ErrMaxValueExceeded = errors.New("validator: maximum value exceeded")
+
+	// ErrMaxDigitsExceeded is reported when a field exceeds the maximum allowed number of digits.
+	ErrMaxDigitsExceeded = errors.New("validator: maximum digits exceeded")
+
+	// ErrMaxLengthExceeded is reported when a field exceeds the maximum allowed length.
+	ErrMaxLengthExceeded = errors.New("validator: maximum length exceeded")
+
+	// ErrDuplicateKey is reported when a JSON object contains the same key more than once.
+	ErrDuplicateKey = errors.New("validator: duplicate key")
+
+	// ErrJSONLimitExceeded is reported when a JSON request body exceeds one of the JSONLimits.
+	ErrJSONLimitExceeded = errors.New("validator: JSON limit exceeded")
+
+	// ErrBodyTooLarge is reported when the request body exceeds the maximum allowed size.
+	ErrBodyTooLarge = errors.New("validator: request body too large")
+
+	// ErrUnsupportedMediaType is reported when the request body is of a media type or in a charset that is not accepted.
+	ErrUnsupportedMediaType = errors.New("validator: unsupported media type")
+
+	// ErrTypeMismatch is reported when a field is not of the required type.
+	ErrTypeMismatch = errors.New("validator: type mismatch")
+
+	// ErrTooManyFiles is reported when a field contains more files than allowed.
+	ErrTooManyFiles = errors.New("validator: too many files")
+
+	// ErrFileTooLarge is reported when an uploaded file, or the uploaded files together, exceed the maximum allowed size.
+	ErrFileTooLarge = errors.New("validator: file too large")
+
+	// ErrFileNotAllowed is reported when an uploaded file has an extension, a content type, or an image format that is not accepted,
+	// or is an animated image where only still images are accepted.
+	ErrFileNotAllowed = errors.New("validator: file not allowed")
+
+	// ErrInvalidFilename is reported when the name of an uploaded file contains characters that are not allowed.
+	ErrInvalidFilename = errors.New("validator: invalid file name")
+
+	// ErrInvalidImage is reported when an uploaded file is not an image of a known format.
+	ErrInvalidImage = errors.New("validator: invalid image")
+
+	// ErrImageTooLarge is reported when an uploaded image exceeds the maximum allowed dimensions.
+	ErrImageTooLarge = errors.New("validator: image too large")
+
+	// ErrItemCount is reported when an array field has fewer or more items than allowed.
+	ErrItemCount = errors.New("validator: invalid number of items")
+
+	// ErrDuplicateItems is reported when an array field contains the same value more than once.
+	ErrDuplicateItems = errors.New("validator: duplicate items")
+
+	// ErrSchemaViolation is reported when a part of the request violates its schema in the API specification.
+	ErrSchemaViolation = errors.New("validator: schema violation")
+
+	// ErrOperationNotFound is reported when a request matches no operation of the API specification.
+	ErrOperationNotFound = errors.New("validator: operation not found")
+)
+
+// codeSentinels maps the built-in error codes to their sentinel errors.
+var codeSentinels = map[string]error{
+	CodeInvalidJSONBody:             ErrInvalidBody,
+	CodeInvalidXMLBody:              ErrInvalidBody,
+	CodeInvalidMsgPackBody:          ErrInvalidBody,
+	CodeInvalidCBORBody:             ErrInvalidBody,
+	CodeInvalidYAMLBody:             ErrInvalidBody,
+	CodeInvalidTOMLBody:             ErrInvalidBody,
+	CodeInvalidBody:                 ErrInvalidBody,
+	CodeUnicodeNotAllowed:           ErrUnicodeNotAllowed,
+	CodeNumberOnly:                  ErrNotNumeric,
+	CodeMaxValueExceeded:            ErrMaxValueExceeded,
+	CodeMaxDigitsExceeded:           ErrMaxDigitsExceeded,
+	CodeMaxLengthExceeded:           ErrMaxLengthExceeded,
+	CodeDuplicateJSONKey:            ErrDuplicateKey,
+	CodeJSONMaxDepthExceeded:        ErrJSONLimitExceeded,
+	CodeJSONMaxKeysExceeded:         ErrJSONLimitExceeded,
+	CodeJSONMaxArrayLengthExceeded:  ErrJSONLimitExceeded,
+	CodeJSONMaxStringLengthExceeded: ErrJSONLimitExceeded,
+	CodeBodyTooLarge:                ErrBodyTooLarge,
+	CodeUnsupportedMediaType:        ErrUnsupportedMediaType,
+	CodeUnsupportedCharset:          ErrUnsupportedMediaType,
+	CodeTypeMismatch:                ErrTypeMismatch,
+	CodeTooManyFiles:                ErrTooManyFiles,
+	CodeFileTooLarge:                ErrFileTooLarge,
+	CodeFilesTooLarge:               ErrFileTooLarge,
+	CodeFileExtensionNotAllowed:     ErrFileNotAllowed,
+	CodeFileTypeNotAllowed:          ErrFileNotAllowed,
+	CodeInvalidFilename:             ErrInvalidFilename,
+	CodeInvalidImage:                ErrInvalidImage,
+	CodeImageFormatNotAllowed:       ErrFileNotAllowed,
+	CodeImageWidthExceeded:          ErrImageTooLarge,
+	CodeImageHeightExceeded:         ErrImageTooLarge,
+	CodeImagePixelsExceeded:         ErrImageTooLarge,
+	CodeImageAnimated:               ErrFileNotAllowed,
+	CodeTooFewItems:                 ErrItemCount,
+	CodeTooManyItems:                ErrItemCount,
+	CodeDuplicateItems:              ErrDuplicateItems,
+	CodeSchemaViolation:             ErrSchemaViolation,
+	CodeOperationNotFound:           ErrOperationNotFound,
+	CodeMethodNotAllowed:            ErrOperationNotFound,
+}
+
+// FieldError describes a violation of a rule by a single field. It wraps one of the sentinel errors.
+type FieldError struct {
+	// Field is the name of the field that failed validation.
+	Field string
+
+	// Err is the sentinel error that identifies the violation.
+	Err error
+}
+
+// Error implements the error interface for FieldError.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field '%s': %v", e.Field, e.Err)
+}
+
+// Unwrap returns the sentinel error that identifies the violation.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// LineError describes a violation in a line of an NDJSON request body. It wraps the error of the rule for the line.
+type LineError struct {
+	// Line is the 1-based number of the line in the request body.
+	Line int
+
+	// Err is the error of the rule for the line.
+	Err error
+}
+
+// Error implements the error interface for LineError.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the error of the rule for the line.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// BodyParseError describes a request body that could not be decoded. It matches ErrInvalidBody
+// and wraps the underlying decode error.
+type BodyParseError struct {
+	// Format is the format of the request body, such as "JSON" or "XML".
+	Format string
+
+	// Err is the underlying decode error.
+	Err error
+}
+
+// Error implements the error interface for BodyParseError.
+func (e *BodyParseError) Error() string {
+	return fmt.Sprintf("invalid %s request body: %v", e.Format, e.Err)
+}
+
+// Unwrap returns the underlying decode error.
+func (e *BodyParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrInvalidBody.
+func (e *BodyParseError) Is(target error) bool {
+	return target == ErrInvalidBody
+}
@@ -4,14 +4,18 @@ go 1.22.3
 toolchain go1.23.7
 
 require (
+	github.com/andybalholm/brotli v1.1.0
 	github.com/bytedance/sonic v1.13.1
 	github.com/clbanning/mxj v1.8.4
+	github.com/fxamacker/cbor/v2 v2.7.0
 	github.com/gofiber/fiber/v2 v2.52.6
+	github.com/pelletier/go-toml/v2 v2.2.3
+	github.com/vmihailenco/msgpack/v5 v5.4.1
 	golang.org/x/text v0.23.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/andybalholm/brotli v1.1.0 // indirect
 	github.com/bytedance/sonic/loader v0.2.4 // indirect
 	github.com/cloudwego/base64x v0.1.5 // indirect
 	github.com/google/uuid v1.6.0 // indirect
@@ -25,6 +29,8 @@ require (
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	github.com/valyala/fasthttp v1.51.0 // indirect
 	github.com/valyala/tcplisten v1.0.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 	golang.org/x/arch v0.0.0-20210923205945-b76863e36670 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 )
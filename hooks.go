@@ -0,0 +1,76 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"reflect"
+	"time"
+)
+
+// NamedRestrictor is an optional interface that a Restrictor can implement to report its own name
+// in hooks and metrics instead of its Go type name.
+type NamedRestrictor interface {
+	Restrictor
+	Name() string
+}
+
+// Evaluation describes the outcome of a single rule evaluation and is passed to the observability hooks.
+type Evaluation struct {
+	// Rule is the name of the rule, either from NamedRestrictor or the Go type name of the rule.
+	Rule string
+
+	// Fields are the fields inspected by the rule, if the rule declares a Fields slice.
+	Fields []string
+
+	// Field is the field that failed validation, if the violation concerns a single field.
+	Field string
+
+	// Duration is the time spent evaluating the rule.
+	Duration time.Duration
+
+	// Err is the violation returned by the rule, or nil when the rule passed.
+	Err error
+
+	// Reported is true when the violation was only reported because of report-only mode.
+	Reported bool
+}
+
+// Outcome returns "passed", "reported", or "failed" depending on the result of the evaluation.
+func (e Evaluation) Outcome() string {
+	switch {
+	case e.Err == nil:
+		return "passed"
+	case e.Reported:
+		return "reported"
+	default:
+		return "failed"
+	}
+}
+
+// ruleName returns the identity of a rule used in hooks and metrics.
+func ruleName(rule Restrictor) string {
+	if n, ok := rule.(NamedRestrictor); ok {
+		return n.Name()
+	}
+	t := reflect.TypeOf(rule)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// ruleFields returns the Fields slice of a rule, following the convention used by the built-in rules.
+func ruleFields(rule Restrictor) []string {
+	v := reflect.Indirect(reflect.ValueOf(rule))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	f := v.FieldByName("Fields")
+	if !f.IsValid() {
+		return nil
+	}
+	fields, _ := f.Interface().([]string)
+	return fields
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SlogHook returns an OnRuleEvaluated hook that writes every rule evaluation to the given structured logger.
+// Passed rules are logged at debug level, reported violations at info level, and rejections at warn level.
+// Records are logged with the user context of the request, so that request-scoped values such as trace IDs reach the handler.
+//
+// Example:
+//
+//	app.Use(validator.New(validator.Config{
+//		Rules:           rules,
+//		OnRuleEvaluated: validator.SlogHook(slog.Default()),
+//	}))
+func SlogHook(logger *slog.Logger) func(c *fiber.Ctx, e Evaluation) {
+	return func(c *fiber.Ctx, e Evaluation) {
+		level := slog.LevelDebug
+		switch e.Outcome() {
+		case "reported":
+			level = slog.LevelInfo
+		case "failed":
+			level = slog.LevelWarn
+		}
+
+		ctx := c.UserContext()
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+
+		attrs := []slog.Attr{
+			slog.String("rule", e.Rule),
+			slog.Any("fields", e.Fields),
+			slog.String("outcome", e.Outcome()),
+			slog.Duration("duration", e.Duration),
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+		}
+		if e.Field != "" {
+			attrs = append(attrs, slog.String("field", e.Field))
+		}
+		if e.Err != nil {
+			attrs = append(attrs, slog.String("error", e.Err.Error()))
+		}
+		logger.LogAttrs(ctx, level, "validator rule evaluated", attrs...)
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Localizer translates the messages of validation errors by error code using a golang.org/x/text message catalog.
+//
+// Every translation is a format string that receives the field name as its first argument and the limit
+// of the violated constraint (if any), its "max" or "min" param, as its second argument, for example "Das Feld '%[1]s' darf höchstens %[2]d Zeichen lang sein".
+// The rejected detail of the request (if any), its "mediaType", "charset", or "type" param, is passed as the third argument,
+// for example "Nicht unterstützter Zeichensatz '%[3]s'".
+// Messages of errors without a translation for the matched language are left unchanged.
+//
+// The number of the invalid line of an NDJSON request body is added to translated messages with the translation of
+// ErrInvalidNDJSONLine, which receives the line number and the translated message, for example "Zeile %[1]d: %[2]s".
+// Likewise, the type of a nullable field is passed as the third argument after translating it with ErrTypeOrNull,
+// for example "%[1]s oder null".
+type Localizer struct {
+	builder *catalog.Builder
+
+	mu      sync.RWMutex
+	known   map[language.Tag]map[string]struct{}
+	tags    []language.Tag
+	matcher language.Matcher
+}
+
+// defaultTranslations are the built-in translations of the built-in error codes and of the NDJSON line prefix.
+var defaultTranslations = map[language.Tag]map[string]string{
+	language.English: {
+		ErrInvalidNDJSONLine:            "Line %[1]d: %[2]s",
+		ErrTypeOrNull:                   "%[1]s or null",
+		CodeInvalidJSONBody:             ErrInvalidJSONBody,
+		CodeInvalidXMLBody:              ErrInvalidXMLBody,
+		CodeInvalidMsgPackBody:          ErrInvalidMsgPackBody,
+		CodeInvalidCBORBody:             ErrInvalidCBORBody,
+		CodeInvalidYAMLBody:             ErrInvalidYAMLBody,
+		CodeInvalidTOMLBody:             ErrInvalidTOMLBody,
+		CodeInvalidBody:                 ErrInvalidRequestBody,
+		CodeUnicodeNotAllowed:           "Unicode characters are not allowed in the '%[1]s' field",
+		CodeNumberOnly:                  "The '%[1]s' field must contain only numbers",
+		CodeMaxValueExceeded:            "The '%[1]s' field must not exceed %[2]d",
+		CodeMaxDigitsExceeded:           "The '%[1]s' field must not exceed %[2]d digits",
+		CodeMaxLengthExceeded:           "The '%[1]s' field must not exceed %[2]d characters",
+		CodeDuplicateJSONKey:            "Duplicate key '%[1]s' in JSON request body",
+		CodeJSONMaxDepthExceeded:        "JSON request body must not exceed a nesting depth of %[2]d",
+		CodeJSONMaxKeysExceeded:         "JSON objects must not contain more than %[2]d keys",
+		CodeJSONMaxArrayLengthExceeded:  "JSON arrays must not contain more than %[2]d elements",
+		CodeJSONMaxStringLengthExceeded: "JSON strings must not exceed %[2]d bytes",
+		CodeBodyTooLarge:                "Request body must not exceed %[2]d bytes",
+		CodeSchemaViolation:             "The '%[1]s' field does not match the API specification",
+		CodeOperationNotFound:           "No operation is defined for this request",
+		CodeMethodNotAllowed:            "The request method is not allowed for this path",
+		CodeUnsupportedCharset:          "Unsupported charset '%[3]s'",
+		CodeUnsupportedMediaType:        "Unsupported media type '%[3]s'",
+		CodeTooManyFiles:                "The '%[1]s' field must not contain more than %[2]d files",
+		CodeFileTooLarge:                "Files in the '%[1]s' field must not exceed %[2]d bytes",
+		CodeFilesTooLarge:               "Uploaded files must not exceed %[2]d bytes in total",
+		CodeFileExtensionNotAllowed:     "Files in the '%[1]s' field must have one of the allowed extensions",
+		CodeFileTypeNotAllowed:          "Files in the '%[1]s' field must be of one of the allowed types",
+		CodeInvalidFilename:             "The '%[1]s' field contains a file with an invalid name",
+		CodeInvalidImage:                "The '%[1]s' field contains a file that is not a valid image",
+		CodeImageFormatNotAllowed:       "Images in the '%[1]s' field must be in one of the allowed formats",
+		CodeImageWidthExceeded:          "Images in the '%[1]s' field must not be wider than %[2]d pixels",
+		CodeImageHeightExceeded:         "Images in the '%[1]s' field must not be taller than %[2]d pixels",
+		CodeImagePixelsExceeded:         "Images in the '%[1]s' field must not exceed %[2]d pixels",
+		CodeImageAnimated:               "Images in the '%[1]s' field must not be animated",
+		CodeTypeMismatch:                "The '%[1]s' field must be of type %[3]s",
+		CodeTooFewItems:                 "The '%[1]s' field must contain at least %[2]d items",
+		CodeTooManyItems:                "The '%[1]s' field must not contain more than %[2]d items",
+		CodeDuplicateItems:              "The '%[1]s' field must not contain duplicate items",
+	},
+	language.Indonesian: {
+		ErrInvalidNDJSONLine:            "Baris %[1]d: %[2]s",
+		ErrTypeOrNull:                   "%[1]s atau null",
+		CodeInvalidJSONBody:             "Body permintaan JSON tidak valid",
+		CodeInvalidXMLBody:              "Body permintaan XML tidak valid",
+		CodeInvalidMsgPackBody:          "Body permintaan MessagePack tidak valid",
+		CodeInvalidCBORBody:             "Body permintaan CBOR tidak valid",
+		CodeInvalidYAMLBody:             "Body permintaan YAML tidak valid",
+		CodeInvalidTOMLBody:             "Body permintaan TOML tidak valid",
+		CodeInvalidBody:                 "Body permintaan tidak valid",
+		CodeUnicodeNotAllowed:           "Karakter Unicode tidak diperbolehkan pada kolom '%[1]s'",
+		CodeNumberOnly:                  "Kolom '%[1]s' hanya boleh berisi angka",
+		CodeMaxValueExceeded:            "Kolom '%[1]s' tidak boleh melebihi %[2]d",
+		CodeMaxDigitsExceeded:           "Kolom '%[1]s' tidak boleh melebihi %[2]d digit",
+		CodeMaxLengthExceeded:           "Kolom '%[1]s' tidak boleh melebihi %[2]d karakter",
+		CodeDuplicateJSONKey:            "Kunci '%[1]s' duplikat pada body permintaan JSON",
+		CodeJSONMaxDepthExceeded:        "Body permintaan JSON tidak boleh melebihi kedalaman %[2]d tingkat",
+		CodeJSONMaxKeysExceeded:         "Objek JSON tidak boleh berisi lebih dari %[2]d kunci",
+		CodeJSONMaxArrayLengthExceeded:  "Array JSON tidak boleh berisi lebih dari %[2]d elemen",
+		CodeJSONMaxStringLengthExceeded: "String JSON tidak boleh melebihi %[2]d byte",
+		CodeBodyTooLarge:                "Body permintaan tidak boleh melebihi %[2]d byte",
+		CodeSchemaViolation:             "Kolom '%[1]s' tidak sesuai dengan spesifikasi API",
+		CodeOperationNotFound:           "Tidak ada operasi yang ditentukan untuk permintaan ini",
+		CodeMethodNotAllowed:            "Metode permintaan tidak diizinkan untuk jalur ini",
+		CodeUnsupportedCharset:          "Charset '%[3]s' tidak didukung",
+		CodeUnsupportedMediaType:        "Tipe media '%[3]s' tidak didukung",
+		CodeTooManyFiles:                "Kolom '%[1]s' tidak boleh berisi lebih dari %[2]d file",
+		CodeFileTooLarge:                "File pada kolom '%[1]s' tidak boleh melebihi %[2]d byte",
+		CodeFilesTooLarge:               "Total ukuran file yang diunggah tidak boleh melebihi %[2]d byte",
+		CodeFileExtensionNotAllowed:     "File pada kolom '%[1]s' harus memiliki salah satu ekstensi yang diizinkan",
+		CodeFileTypeNotAllowed:          "File pada kolom '%[1]s' harus berjenis salah satu tipe yang diizinkan",
+		CodeInvalidFilename:             "Kolom '%[1]s' berisi file dengan nama yang tidak valid",
+		CodeInvalidImage:                "Kolom '%[1]s' berisi file yang bukan gambar yang valid",
+		CodeImageFormatNotAllowed:       "Gambar pada kolom '%[1]s' harus berformat salah satu format yang diizinkan",
+		CodeImageWidthExceeded:          "Lebar gambar pada kolom '%[1]s' tidak boleh melebihi %[2]d piksel",
+		CodeImageHeightExceeded:         "Tinggi gambar pada kolom '%[1]s' tidak boleh melebihi %[2]d piksel",
+		CodeImagePixelsExceeded:         "Gambar pada kolom '%[1]s' tidak boleh melebihi %[2]d piksel",
+		CodeImageAnimated:               "Gambar pada kolom '%[1]s' tidak boleh beranimasi",
+		CodeTypeMismatch:                "Kolom '%[1]s' harus bertipe %[3]s",
+		CodeTooFewItems:                 "Kolom '%[1]s' harus berisi minimal %[2]d item",
+		CodeTooManyItems:                "Kolom '%[1]s' tidak boleh berisi lebih dari %[2]d item",
+		CodeDuplicateItems:              "Kolom '%[1]s' tidak boleh berisi item duplikat",
+	},
+	language.Japanese: {
+		ErrInvalidNDJSONLine:            "%[1]d 行目: %[2]s",
+		ErrTypeOrNull:                   "%[1]s または null",
+		CodeInvalidJSONBody:             "JSONリクエストボディが不正です",
+		CodeInvalidXMLBody:              "XMLリクエストボディが不正です",
+		CodeInvalidMsgPackBody:          "MessagePackリクエストボディが不正です",
+		CodeInvalidCBORBody:             "CBORリクエストボディが不正です",
+		CodeInvalidYAMLBody:             "YAMLリクエストボディが不正です",
+		CodeInvalidTOMLBody:             "TOMLリクエストボディが不正です",
+		CodeInvalidBody:                 "リクエストボディが不正です",
+		CodeUnicodeNotAllowed:           "'%[1]s' フィールドにはUnicode文字を使用できません",
+		CodeNumberOnly:                  "'%[1]s' フィールドには数字のみを入力してください",
+		CodeMaxValueExceeded:            "'%[1]s' フィールドは %[2]d 以下である必要があります",
+		CodeMaxDigitsExceeded:           "'%[1]s' フィールドは %[2]d 桁以内である必要があります",
+		CodeMaxLengthExceeded:           "'%[1]s' フィールドは %[2]d 文字以内である必要があります",
+		CodeDuplicateJSONKey:            "JSONリクエストボディのキー '%[1]s' が重複しています",
+		CodeJSONMaxDepthExceeded:        "JSONリクエストボディのネストの深さは %[2]d 以下である必要があります",
+		CodeJSONMaxKeysExceeded:         "JSONオブジェクトのキーは %[2]d 個以下である必要があります",
+		CodeJSONMaxArrayLengthExceeded:  "JSON配列の要素は %[2]d 個以下である必要があります",
+		CodeJSONMaxStringLengthExceeded: "JSON文字列は %[2]d バイト以下である必要があります",
+		CodeBodyTooLarge:                "リクエストボディは %[2]d バイト以下である必要があります",
+		CodeSchemaViolation:             "'%[1]s' フィールドが API 仕様に適合していません",
+		CodeOperationNotFound:           "このリクエストに対応する操作は定義されていません",
+		CodeMethodNotAllowed:            "このパスではリクエストのメソッドは許可されていません",
+		CodeUnsupportedCharset:          "文字セット '%[3]s' はサポートされていません",
+		CodeUnsupportedMediaType:        "メディアタイプ '%[3]s' はサポートされていません",
+		CodeTooManyFiles:                "'%[1]s' フィールドのファイルは %[2]d 個以下である必要があります",
+		CodeFileTooLarge:                "'%[1]s' フィールドのファイルは %[2]d バイト以下である必要があります",
+		CodeFilesTooLarge:               "アップロードされたファイルの合計は %[2]d バイト以下である必要があります",
+		CodeFileExtensionNotAllowed:     "'%[1]s' フィールドのファイルの拡張子は許可されていません",
+		CodeFileTypeNotAllowed:          "'%[1]s' フィールドのファイルの種類は許可されていません",
+		CodeInvalidFilename:             "'%[1]s' フィールドのファイル名が不正です",
+		CodeInvalidImage:                "'%[1]s' フィールドのファイルは有効な画像ではありません",
+		CodeImageFormatNotAllowed:       "'%[1]s' フィールドの画像の形式は許可されていません",
+		CodeImageWidthExceeded:          "'%[1]s' フィールドの画像の幅は %[2]d ピクセル以下である必要があります",
+		CodeImageHeightExceeded:         "'%[1]s' フィールドの画像の高さは %[2]d ピクセル以下である必要があります",
+		CodeImagePixelsExceeded:         "'%[1]s' フィールドの画像は %[2]d ピクセル以下である必要があります",
+		CodeImageAnimated:               "'%[1]s' フィールドにアニメーション画像は使用できません",
+		CodeTypeMismatch:                "'%[1]s' フィールドは %[3]s 型でなければなりません",
+		CodeTooFewItems:                 "'%[1]s' フィールドには %[2]d 個以上の要素が必要です",
+		CodeTooManyItems:                "'%[1]s' フィールドの要素は %[2]d 個以下にしてください",
+		CodeDuplicateItems:              "'%[1]s' フィールドに重複した要素を含めることはできません",
+	},
+	language.German: {
+		ErrInvalidNDJSONLine:            "Zeile %[1]d: %[2]s",
+		ErrTypeOrNull:                   "%[1]s oder null",
+		CodeInvalidJSONBody:             "Ungültiger JSON-Anfragetext",
+		CodeInvalidXMLBody:              "Ungültiger XML-Anfragetext",
+		CodeInvalidMsgPackBody:          "Ungültiger MessagePack-Anfragetext",
+		CodeInvalidCBORBody:             "Ungültiger CBOR-Anfragetext",
+		CodeInvalidYAMLBody:             "Ungültiger YAML-Anfragetext",
+		CodeInvalidTOMLBody:             "Ungültiger TOML-Anfragetext",
+		CodeInvalidBody:                 "Ungültiger Anfragetext",
+		CodeUnicodeNotAllowed:           "Unicode-Zeichen sind im Feld '%[1]s' nicht erlaubt",
+		CodeNumberOnly:                  "Das Feld '%[1]s' darf nur Ziffern enthalten",
+		CodeMaxValueExceeded:            "Das Feld '%[1]s' darf %[2]d nicht überschreiten",
+		CodeMaxDigitsExceeded:           "Das Feld '%[1]s' darf höchstens %[2]d Ziffern enthalten",
+		CodeMaxLengthExceeded:           "Das Feld '%[1]s' darf höchstens %[2]d Zeichen lang sein",
+		CodeDuplicateJSONKey:            "Doppelter Schlüssel '%[1]s' im JSON-Anfragetext",
+		CodeJSONMaxDepthExceeded:        "Der JSON-Anfragetext darf eine Verschachtelungstiefe von %[2]d nicht überschreiten",
+		CodeJSONMaxKeysExceeded:         "JSON-Objekte dürfen nicht mehr als %[2]d Schlüssel enthalten",
+		CodeJSONMaxArrayLengthExceeded:  "JSON-Arrays dürfen nicht mehr als %[2]d Elemente enthalten",
+		CodeJSONMaxStringLengthExceeded: "JSON-Zeichenketten dürfen %[2]d Bytes nicht überschreiten",
+		CodeBodyTooLarge:                "Der Anfragetext darf %[2]d Bytes nicht überschreiten",
+		CodeSchemaViolation:             "Das Feld '%[1]s' entspricht nicht der API-Spezifikation",
+		CodeOperationNotFound:           "Für diese Anfrage ist keine Operation definiert",
+		CodeMethodNotAllowed:            "Die Methode der Anfrage ist für diesen Pfad nicht erlaubt",
+		CodeUnsupportedCharset:          "Nicht unterstützter Zeichensatz '%[3]s'",
+		CodeUnsupportedMediaType:        "Nicht unterstützter Medientyp '%[3]s'",
+		CodeTooManyFiles:                "Das Feld '%[1]s' darf nicht mehr als %[2]d Dateien enthalten",
+		CodeFileTooLarge:                "Dateien im Feld '%[1]s' dürfen %[2]d Bytes nicht überschreiten",
+		CodeFilesTooLarge:               "Die hochgeladenen Dateien dürfen insgesamt %[2]d Bytes nicht überschreiten",
+		CodeFileExtensionNotAllowed:     "Dateien im Feld '%[1]s' müssen eine der erlaubten Dateiendungen haben",
+		CodeFileTypeNotAllowed:          "Dateien im Feld '%[1]s' müssen einen der erlaubten Dateitypen haben",
+		CodeInvalidFilename:             "Das Feld '%[1]s' enthält eine Datei mit ungültigem Namen",
+		CodeInvalidImage:                "Das Feld '%[1]s' enthält eine Datei, die kein gültiges Bild ist",
+		CodeImageFormatNotAllowed:       "Bilder im Feld '%[1]s' müssen in einem der erlaubten Formate vorliegen",
+		CodeImageWidthExceeded:          "Bilder im Feld '%[1]s' dürfen höchstens %[2]d Pixel breit sein",
+		CodeImageHeightExceeded:         "Bilder im Feld '%[1]s' dürfen höchstens %[2]d Pixel hoch sein",
+		CodeImagePixelsExceeded:         "Bilder im Feld '%[1]s' dürfen %[2]d Pixel nicht überschreiten",
+		CodeImageAnimated:               "Bilder im Feld '%[1]s' dürfen nicht animiert sein",
+		CodeTypeMismatch:                "Das Feld '%[1]s' muss vom Typ %[3]s sein",
+		CodeTooFewItems:                 "Das Feld '%[1]s' muss mindestens %[2]d Einträge enthalten",
+		CodeTooManyItems:                "Das Feld '%[1]s' darf höchstens %[2]d Einträge enthalten",
+		CodeDuplicateItems:              "Das Feld '%[1]s' darf keine doppelten Einträge enthalten",
+	},
+}
+
+// NewLocalizer creates a new Localizer with the built-in English, Indonesian, Japanese, and German translations.
+func NewLocalizer() *Localizer {
+	l := &Localizer{
+		builder: catalog.NewBuilder(catalog.Fallback(language.English)),
+		known:   make(map[language.Tag]map[string]struct{}),
+	}
+	for tag, translations := range defaultTranslations {
+		for code, format := range translations {
+			// The built-in translations are valid, so registering them cannot fail.
+			_ = l.Register(tag, code, format)
+		}
+	}
+	return l
+}
+
+// Register adds or overrides the translation of an error code for a language.
+// It is safe to call while the middleware is serving requests.
+func (l *Localizer) Register(tag language.Tag, code, format string) error {
+	if err := l.builder.SetString(tag, code, format); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.known[tag] == nil {
+		l.known[tag] = make(map[string]struct{})
+		// A new language invalidates the matcher.
+		l.tags, l.matcher = nil, nil
+	}
+	l.known[tag][code] = struct{}{}
+	return nil
+}
+
+// Match returns the supported language that best matches the preferred languages,
+// and false if none of them is supported.
+func (l *Localizer) Match(preferred ...language.Tag) (language.Tag, bool) {
+	tags, matcher := l.languages()
+	_, index, confidence := matcher.Match(preferred...)
+	if confidence == language.No {
+		return language.Und, false
+	}
+	return tags[index], true
+}
+
+// Localize returns the message of e translated to the supported language that best matches the preferred languages.
+// The original message is returned when the error has no code or no translation is available.
+func (l *Localizer) Localize(e *Error, preferred ...language.Tag) string {
+	if e.Code == "" {
+		return e.Message
+	}
+
+	matched, ok := l.Match(preferred...)
+	if !ok {
+		return e.Message
+	}
+
+	l.mu.RLock()
+	_, found := l.known[matched][e.Code]
+	l.mu.RUnlock()
+	if !found {
+		return e.Message
+	}
+
+	field := e.Field
+	if fields, ok := e.Params["fields"].([]string); ok {
+		field = strings.Join(fields, "', '")
+	}
+
+	limit, ok := e.Params["max"]
+	if !ok {
+		limit = e.Params["min"]
+	}
+
+	var detail interface{}
+	for _, param := range []string{"mediaType", "charset", "type"} {
+		if v, ok := e.Params[param]; ok {
+			detail = v
+			break
+		}
+	}
+
+	p := message.NewPrinter(matched, message.Catalog(l.builder))
+	// The expected type of a nullable field is translated together with its "or null" part.
+	if nullable, _ := e.Params["nullable"].(bool); nullable && detail != nil {
+		detail = p.Sprintf(ErrTypeOrNull, detail)
+	}
+	msg := p.Sprintf(e.Code, field, limit, detail)
+	// Errors of NDJSON request bodies keep the number of the invalid line.
+	if line, ok := e.Params["line"].(int); ok {
+		msg = p.Sprintf(ErrInvalidNDJSONLine, line, msg)
+	}
+	return msg
+}
+
+// languages returns the supported languages and a matcher for them, building the matcher if needed.
+func (l *Localizer) languages() ([]language.Tag, language.Matcher) {
+	l.mu.RLock()
+	tags, matcher := l.tags, l.matcher
+	l.mu.RUnlock()
+	if matcher != nil {
+		return tags, matcher
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.matcher == nil {
+		// English comes first so that it is the default of the matcher.
+		var others []language.Tag
+		for tag := range l.known {
+			if tag != language.English {
+				others = append(others, tag)
+			}
+		}
+		sort.Slice(others, func(i, j int) bool { return others[i].String() < others[j].String() })
+		l.tags = append([]language.Tag{language.English}, others...)
+		l.matcher = language.NewMatcher(l.tags)
+	}
+	return l.tags, l.matcher
+}
+
+// preferredLanguages returns the preferred languages of the request, either from Config.Language
+// or from the Accept-Language header.
+func preferredLanguages(c *fiber.Ctx, cfg Config) []language.Tag {
+	if cfg.Language != nil {
+		return []language.Tag{cfg.Language(c)}
+	}
+	return acceptLanguage(c)
+}
+
+// acceptLanguage returns the languages of the request's Accept-Language header in order of preference.
+func acceptLanguage(c *fiber.Ctx) []language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+	if err != nil {
+		return nil
+	}
+	return tags
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultMetricsBuckets are the default histogram buckets, in seconds, for rule evaluation durations.
+var DefaultMetricsBuckets = []float64{0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1}
+
+// Metrics is an in-process registry of rule evaluation counters and duration histograms
+// that can be exposed in the Prometheus text exposition format without extra dependencies.
+//
+// Example:
+//
+//	metrics := validator.NewMetrics()
+//
+//	app.Use(validator.New(validator.Config{
+//		Rules:           rules,
+//		OnRuleEvaluated: metrics.OnRuleEvaluated,
+//	}))
+//
+//	app.Get("/metrics", metrics.Handler())
+type Metrics struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  map[metricsCounterKey]uint64
+	rules   map[string]*metricsHistogram
+}
+
+// metricsCounterKey identifies a rule evaluation counter.
+type metricsCounterKey struct {
+	rule    string
+	outcome string
+}
+
+// metricsHistogram holds the duration histogram of a single rule.
+type metricsHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// NewMetrics creates a new Metrics registry. If no buckets are provided, DefaultMetricsBuckets is used.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsBuckets
+	}
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	return &Metrics{
+		buckets: b,
+		counts:  make(map[metricsCounterKey]uint64),
+		rules:   make(map[string]*metricsHistogram),
+	}
+}
+
+// OnRuleEvaluated records a rule evaluation. It has the signature of Config.OnRuleEvaluated.
+func (m *Metrics) OnRuleEvaluated(c *fiber.Ctx, e Evaluation) {
+	seconds := e.Duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counts[metricsCounterKey{rule: e.Rule, outcome: e.Outcome()}]++
+
+	h, ok := m.rules[e.Rule]
+	if !ok {
+		h = &metricsHistogram{buckets: make([]uint64, len(m.buckets))}
+		m.rules[e.Rule] = h
+	}
+	for i, upper := range m.buckets {
+		if seconds <= upper {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// Count returns the number of evaluations recorded for a rule with the given outcome
+// ("passed", "reported", or "failed").
+func (m *Metrics) Count(rule, outcome string) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[metricsCounterKey{rule: rule, outcome: outcome}]
+}
+
+// WritePrometheus writes all metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	keys := make([]metricsCounterKey, 0, len(m.counts))
+	for k := range m.counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rule != keys[j].rule {
+			return keys[i].rule < keys[j].rule
+		}
+		return keys[i].outcome < keys[j].outcome
+	})
+
+	fmt.Fprintln(bw, "# HELP validator_rule_evaluations_total Total number of validator rule evaluations.")
+	fmt.Fprintln(bw, "# TYPE validator_rule_evaluations_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(bw, "validator_rule_evaluations_total{rule=\"%s\",outcome=\"%s\"} %d\n",
+			escapeLabelValue(k.rule), escapeLabelValue(k.outcome), m.counts[k])
+	}
+
+	rules := make([]string, 0, len(m.rules))
+	for rule := range m.rules {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	fmt.Fprintln(bw, "# HELP validator_rule_duration_seconds Duration of validator rule evaluations in seconds.")
+	fmt.Fprintln(bw, "# TYPE validator_rule_duration_seconds histogram")
+	for _, rule := range rules {
+		h := m.rules[rule]
+		label := escapeLabelValue(rule)
+		for i, upper := range m.buckets {
+			fmt.Fprintf(bw, "validator_rule_duration_seconds_bucket{rule=\"%s\",le=\"%s\"} %d\n",
+				label, strconv.FormatFloat(upper, 'g', -1, 64), h.buckets[i])
+		}
+		fmt.Fprintf(bw, "validator_rule_duration_seconds_bucket{rule=\"%s\",le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(bw, "validator_rule_duration_seconds_sum{rule=\"%s\"} %s\n", label, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(bw, "validator_rule_duration_seconds_count{rule=\"%s\"} %d\n", label, h.count)
+	}
+
+	return bw.Flush()
+}
+
+// Handler returns a Fiber handler that serves the metrics in the Prometheus text exposition format.
+func (m *Metrics) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+		return m.WritePrometheus(c)
+	}
+}
+
+// escapeLabelValue escapes a Prometheus label value.
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
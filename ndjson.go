@@ -0,0 +1,159 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestRestrictor is implemented by internal rules that apply to the request as a whole,
+// so they are not evaluated for every line of an NDJSON request body.
+type requestRestrictor interface {
+	requestLevel()
+}
+
+// isRequestLevel reports whether the rule applies to the request as a whole.
+func isRequestLevel(rule Restrictor) bool {
+	_, ok := rule.(requestRestrictor)
+	return ok
+}
+
+// ndjsonLine is a non-empty line of an NDJSON request body with its 1-based line number.
+type ndjsonLine struct {
+	number int
+	data   []byte
+}
+
+// ndjsonLines splits an NDJSON request body into its non-empty lines.
+// The body is copied, since the request body is replaced while the lines are validated.
+func ndjsonLines(c *fiber.Ctx) []ndjsonLine {
+	body := append([]byte(nil), c.Body()...)
+
+	var lines []ndjsonLine
+	for i, data := range bytes.Split(body, []byte("\n")) {
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		lines = append(lines, ndjsonLine{number: i + 1, data: data})
+	}
+	return lines
+}
+
+// restrictLines evaluates the rule for every line of an NDJSON request body as a separate JSON request body.
+// It stops after maxErrors invalid lines, or at the first invalid line when maxErrors is less than 1.
+// The request body and its headers are restored afterwards.
+func restrictLines(c *fiber.Ctx, rule Restrictor, lines []ndjsonLine, maxErrors int) error {
+	if maxErrors < 1 {
+		maxErrors = 1
+	}
+
+	// The lines are already decoded, so they are validated as plain JSON request bodies.
+	setBody := swapJSONBody(c)
+	defer setBody(nil)
+
+	var errs []*LineError
+	for _, line := range lines {
+		setBody(line.data)
+		if err := rule.Restrict(c); err != nil {
+			errs = append(errs, &LineError{Line: line.number, Err: err})
+			if len(errs) >= maxErrors {
+				break
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errInvalidLines(errs)
+}
+
+// swapJSONBody prepares the request for validating parts of its body as separate JSON request bodies.
+// The returned function replaces the request body with a JSON document, or restores the request body and its headers
+// when it is called with nil. The media types accepted by the rules are still checked against the Content-Type of the request.
+func swapJSONBody(c *fiber.Ctx) func(data []byte) {
+	req := c.Request()
+	body := append([]byte(nil), req.Body()...)
+	contentType := append([]byte(nil), req.Header.ContentType()...)
+	contentEncoding := append([]byte(nil), req.Header.ContentEncoding()...)
+	contentLength := req.Header.ContentLength()
+
+	// Nested swaps keep the Content-Type of the request.
+	original := c.Locals(requestContentTypeKey{})
+	if original == nil {
+		c.Locals(requestContentTypeKey{}, string(contentType))
+	}
+	req.Header.SetContentType(fiber.MIMEApplicationJSON)
+	req.Header.Del(fiber.HeaderContentEncoding)
+
+	return func(data []byte) {
+		if data != nil {
+			req.SetBodyRaw(data)
+			req.Header.SetContentLength(len(data))
+			return
+		}
+		c.Locals(requestContentTypeKey{}, original)
+		req.SetBody(body)
+		req.Header.SetContentTypeBytes(contentType)
+		if len(contentEncoding) > 0 {
+			req.Header.SetContentEncodingBytes(contentEncoding)
+		}
+		req.Header.SetContentLength(contentLength)
+	}
+}
+
+// errInvalidLines returns the error for the invalid lines of an NDJSON request body.
+// The *Error of the first invalid line is reported with its line number, and the errors of the other lines
+// are listed in the "errors" param. Errors of custom rules that are not an *Error are returned as they are.
+func errInvalidLines(errs []*LineError) error {
+	joined := make([]error, len(errs))
+	for i, err := range errs {
+		joined[i] = err
+	}
+
+	var first *Error
+	if !errors.As(errs[0].Err, &first) {
+		return errors.Join(joined...)
+	}
+
+	e := *first
+	e.Message = fmt.Sprintf(ErrInvalidNDJSONLine, errs[0].Line, first.Message)
+	e.Params = make(map[string]interface{}, len(first.Params)+2)
+	for k, v := range first.Params {
+		e.Params[k] = v
+	}
+	e.Params["line"] = errs[0].Line
+	if len(errs) > 1 {
+		details := make([]map[string]interface{}, len(errs))
+		for i, err := range errs {
+			details[i] = lineErrorDetails(err)
+		}
+		e.Params["errors"] = details
+	}
+	e.Err = errors.Join(joined...)
+	return &e
+}
+
+// lineErrorDetails describes the error of an invalid line for the "errors" param.
+func lineErrorDetails(err *LineError) map[string]interface{} {
+	details := map[string]interface{}{"line": err.Line}
+	var ve *Error
+	if !errors.As(err.Err, &ve) {
+		details["error"] = err.Err.Error()
+		return details
+	}
+	details["error"] = ve.Message
+	if ve.Code != "" {
+		details["code"] = ve.Code
+	}
+	if ve.Field != "" {
+		details["field"] = ve.Field
+	}
+	return details
+}
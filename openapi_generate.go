@@ -0,0 +1,197 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Patterns of the OpenAPI schema constraints generated for the built-in rules.
+const (
+	asciiPattern   = `^[\x00-\x7F]*$`
+	numericPattern = `^[0-9]*$`
+)
+
+// OpenAPIFragment is a fragment of an OpenAPI document, such as a schema object or a paths object,
+// that can be merged into a specification.
+type OpenAPIFragment map[string]interface{}
+
+// JSON encodes the fragment as indented JSON.
+func (f OpenAPIFragment) JSON() ([]byte, error) {
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// YAML encodes the fragment as YAML.
+func (f OpenAPIFragment) YAML() ([]byte, error) {
+	return yaml.Marshal(map[string]interface{}(f))
+}
+
+// schemaRule is implemented by rules that can describe their constraints as an OpenAPI schema.
+type schemaRule interface {
+	describeSchema(b *schemaBuilder)
+}
+
+// OpenAPISchema generates the OpenAPI schema object of the request body that is accepted by the rules of a configuration,
+// so that an API specification can be kept in sync with the validator configuration.
+//
+// Every field of the rules becomes a property of an object schema with the constraints of the rules:
+// RestrictStringLength sets maxLength, RestrictNumberOnly sets a numeric pattern, maximum, and a maxLength for MaxDigits,
+// RestrictUnicode sets an ASCII pattern, RestrictType sets the type and nullable, and RestrictArray sets minItems, maxItems,
+// uniqueItems, and the items schema of its element rules. RestrictUnicode and RestrictStringLength
+// with StrictTypes declare nullable strings. When several rules constrain the same field, the strictest limit is kept
+// and additional patterns are combined with allOf. Rules without an equivalent schema constraint, such as custom rules
+// and the body size limits, are not described. Rules in report-only mode are described like the other rules.
+//
+// Note: RestrictStringLength limits the length in bytes, while the maxLength keyword counts characters.
+// Both are the same for ASCII values.
+//
+// Example:
+//
+//	schema, err := validator.OpenAPISchema(cfg).YAML()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	os.WriteFile("schemas/login.yaml", schema, 0o644)
+func OpenAPISchema(cfg Config) OpenAPIFragment {
+	return describeRules(effectiveRules(cfg))
+}
+
+// OpenAPIPaths generates an OpenAPI paths object with the request body schema of every route declared in the Registry,
+// as generated by OpenAPISchema. Fiber route parameters such as ":id" are converted to OpenAPI path templates such as "{id}",
+// and the schema is declared for the application/json media type. Routes without schema constraints are omitted.
+func (r *Registry) OpenAPIPaths() OpenAPIFragment {
+	paths := OpenAPIFragment{}
+	for _, route := range r.routes {
+		schema := describeRules(route.Rules)
+		if _, ok := schema["properties"]; !ok {
+			continue
+		}
+
+		template := openAPIPathTemplate(route.Path)
+		item, _ := paths[template].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[template] = item
+		}
+		item[strings.ToLower(route.Method)] = map[string]interface{}{
+			"requestBody": map[string]interface{}{
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}(schema),
+					},
+				},
+			},
+		}
+	}
+	return paths
+}
+
+// describeRules generates the object schema for the constraints of the rules.
+func describeRules(rules []Restrictor) OpenAPIFragment {
+	b := &schemaBuilder{properties: map[string]map[string]interface{}{}}
+	for _, rule := range rules {
+		if r, ok := rule.(schemaRule); ok {
+			r.describeSchema(b)
+		}
+	}
+
+	schema := OpenAPIFragment{"type": "object"}
+	if len(b.properties) > 0 {
+		properties := make(map[string]interface{}, len(b.properties))
+		for field, property := range b.properties {
+			properties[field] = property
+		}
+		schema["properties"] = properties
+	}
+	return schema
+}
+
+// routeParamPattern matches a Fiber route parameter, with its optional constraints and optional marker.
+var routeParamPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)(?:<[^>]*>)?\??`)
+
+// openAPIPathTemplate converts a Fiber route pattern to an OpenAPI path template.
+func openAPIPathTemplate(path string) string {
+	return routeParamPattern.ReplaceAllString(path, "{$1}")
+}
+
+// schemaBuilder collects the schema constraints of the fields of a request body.
+type schemaBuilder struct {
+	properties map[string]map[string]interface{}
+}
+
+// constrain adds a constraint to the schema of the fields.
+// Limits that are already set keep the strictest value, and different patterns are combined with allOf.
+func (b *schemaBuilder) constrain(fields []string, keyword string, value interface{}) {
+	for _, field := range fields {
+		property := b.properties[field]
+		if property == nil {
+			property = map[string]interface{}{}
+			b.properties[field] = property
+		}
+
+		existing, ok := property[keyword]
+		switch {
+		case !ok:
+			property[keyword] = value
+		case reflect.DeepEqual(existing, value):
+		case keyword == "pattern":
+			allOf, _ := property["allOf"].([]interface{})
+			for _, s := range allOf {
+				if reflect.DeepEqual(s, map[string]interface{}{"pattern": value}) {
+					value = nil
+					break
+				}
+			}
+			if value != nil {
+				property["allOf"] = append(allOf, map[string]interface{}{"pattern": value})
+			}
+		default:
+			// The limits of the built-in rules are upper bounds, except for minItems. Other keywords keep their first value.
+			if limit, ok := value.(int); ok {
+				if existingLimit, ok := existing.(int); ok && (limit < existingLimit) != (keyword == "minItems") {
+					property[keyword] = value
+				}
+			}
+		}
+	}
+}
+
+// describeSchema implements the schemaRule interface for RestrictUnicode.
+func (r RestrictUnicode) describeSchema(b *schemaBuilder) {
+	b.constrain(r.Fields, "pattern", asciiPattern)
+	describeStrictTypes(b, r.Fields, r.StrictTypes)
+}
+
+// describeSchema implements the schemaRule interface for RestrictStringLength.
+func (r RestrictStringLength) describeSchema(b *schemaBuilder) {
+	if r.MaxLength != nil {
+		b.constrain(r.Fields, "maxLength", *r.MaxLength)
+	}
+	describeStrictTypes(b, r.Fields, r.StrictTypes)
+}
+
+// describeStrictTypes declares the fields of a rule with StrictTypes as nullable strings.
+func describeStrictTypes(b *schemaBuilder, fields []string, strict bool) {
+	if strict {
+		b.constrain(fields, "type", string(TypeString))
+		b.constrain(fields, "nullable", true)
+	}
+}
+
+// describeSchema implements the schemaRule interface for RestrictNumberOnly.
+func (r RestrictNumberOnly) describeSchema(b *schemaBuilder) {
+	b.constrain(r.Fields, "pattern", numericPattern)
+	if r.Max != nil {
+		b.constrain(r.Fields, "maximum", *r.Max)
+	}
+	if r.MaxDigits != nil {
+		b.constrain(r.Fields, "maxLength", *r.MaxDigits)
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	// ErrUnknownRoute is returned by Registry.Validate when rules are declared for a route that is not registered in the app.
+	ErrUnknownRoute = errors.New("validator: unknown route")
+
+	// ErrOverlappingRoutes is returned by Registry.Validate when rules are declared for routes that can match the same request.
+	ErrOverlappingRoutes = errors.New("validator: overlapping routes")
+)
+
+// Registry declares validation rules per method and route pattern, so that a single middleware
+// applies the right rules to every endpoint of an app.
+//
+// Route patterns are the patterns the routes were registered with in Fiber, as reported by c.Route().Path.
+// Requests for routes without declared rules are passed through unchanged.
+//
+// Example:
+//
+//	registry := validator.NewRegistry(validator.Config{
+//		MaxBodySize: 4 * 1024,
+//	})
+//	registry.Add(fiber.MethodPost, "/login", validator.RestrictUnicode{Fields: []string{"username"}})
+//	registry.Add(fiber.MethodPost, "/users/:id", validator.RestrictStringLength{Fields: []string{"name"}, MaxLength: &maxLength})
+//
+//	app.Use(registry.Handler(app))
+//	app.Post("/login", login)
+//	app.Post("/users/:id", updateUser)
+//
+//	if err := registry.Validate(app); err != nil {
+//		log.Fatal(err)
+//	}
+type Registry struct {
+	cfg    Config
+	routes []RouteRules
+
+	once      sync.Once
+	appConfig fiber.Config
+	compiled  map[string][]compiledRoute
+	err       error
+}
+
+// RouteRules are the rules declared for a route.
+type RouteRules struct {
+	// Method is the HTTP method of the route.
+	Method string
+
+	// Path is the route pattern, as registered in Fiber.
+	Path string
+
+	// Rules are the effective rules evaluated for the route, in order,
+	// including the body size and JSON limits and the Config.Rules of the Registry.
+	Rules []Restrictor
+}
+
+// Names returns the names of the effective rules of the route, as reported in hooks and metrics.
+func (r RouteRules) Names() []string {
+	names := make([]string, len(r.Rules))
+	for i, rule := range r.Rules {
+		names[i] = ruleName(rule)
+	}
+	return names
+}
+
+// compiledRoute is a route of the app, in the order in which Fiber matches it, with the middleware for its rules.
+type compiledRoute struct {
+	pattern routePattern
+	handler fiber.Handler
+}
+
+// NewRegistry creates a new Registry. The configuration applies to every route,
+// and its Rules are evaluated before the rules declared for a route.
+func NewRegistry(config ...Config) *Registry {
+	return &Registry{cfg: configDefault(config...)}
+}
+
+// Add declares the rules for the route with the given method and pattern.
+// Rules must be declared before the Registry handles its first request or is validated.
+func (r *Registry) Add(method, path string, rules ...Restrictor) *Registry {
+	cfg := r.cfg
+	cfg.Rules = append(append([]Restrictor(nil), r.cfg.Rules...), rules...)
+	r.routes = append(r.routes, RouteRules{
+		Method: strings.ToUpper(method),
+		Path:   path,
+		Rules:  effectiveRules(cfg),
+	})
+	return r
+}
+
+// Routes returns the routes with declared rules and their effective rules, in the order they were declared.
+func (r *Registry) Routes() []RouteRules {
+	return append([]RouteRules(nil), r.routes...)
+}
+
+// Validate checks the declared routes against the routes registered in the app and prepares the Registry
+// for handling requests. It should be called once all routes are registered and before the app starts listening.
+// It returns an error wrapping ErrUnknownRoute or ErrOverlappingRoutes if the declarations are invalid.
+func (r *Registry) Validate(app *fiber.App) error {
+	r.once.Do(func() {
+		r.appConfig = app.Config()
+		r.compiled, r.err = r.compile(app)
+	})
+	return r.err
+}
+
+// Handler creates the Validator middleware for the Registry and validates the Registry against the app when it starts
+// listening, so that the app fails to start if the declarations are invalid. Fiber panics with the error of Validate
+// in that case. Calling Validate explicitly once the routes are registered reports the error without a panic.
+//
+// Note: When the app handles requests without listening, such as with app.Test, the Registry is validated on
+// the first request instead, and every request fails with the validation error if the declarations are invalid.
+func (r *Registry) Handler(app *fiber.App) fiber.Handler {
+	app.Hooks().OnListen(func(fiber.ListenData) error {
+		return r.Validate(app)
+	})
+
+	return func(c *fiber.Ctx) error {
+		if err := r.Validate(c.App()); err != nil {
+			return err
+		}
+
+		path := c.Path()
+		if !r.appConfig.CaseSensitive {
+			path = strings.ToLower(path)
+		}
+
+		// Fiber matches routes in the order they were registered, so the first matching route is the endpoint of the request.
+		for _, route := range r.compiled[c.Method()] {
+			if route.pattern.match(path) {
+				if route.handler == nil {
+					return c.Next()
+				}
+				return route.handler(c)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// compile checks the declared routes and builds the routes of the app, per method, in the order Fiber matches them.
+func (r *Registry) compile(app *fiber.App) (map[string][]compiledRoute, error) {
+	for i, a := range r.routes {
+		for _, b := range r.routes[i+1:] {
+			if a.Method == b.Method && routesOverlap(a.Path, b.Path, r.appConfig) {
+				return nil, fmt.Errorf("%w: %s %s and %s %s", ErrOverlappingRoutes, a.Method, a.Path, b.Method, b.Path)
+			}
+		}
+	}
+
+	handlers := make(map[string]fiber.Handler, len(r.routes))
+	for _, route := range r.routes {
+		cfg := r.cfg
+		cfg.Rules = route.Rules
+		// The effective rules already contain the body size limit, the accepted media types, the transcoding, and the JSON limits.
+		cfg.MaxBodySize, cfg.AllowedContentTypes, cfg.Transcode, cfg.JSONLimits = 0, nil, nil, nil
+		cfg.Reloader = nil
+		handlers[route.Method+" "+route.Path] = newHandler(cfg)
+	}
+
+	compiled := make(map[string][]compiledRoute)
+	for _, route := range app.GetRoutes(true) {
+		key := route.Method + " " + route.Path
+		handler := handlers[key]
+		// A route registered twice is only reachable through its first registration.
+		delete(handlers, key)
+		compiled[route.Method] = append(compiled[route.Method], compiledRoute{
+			pattern: compileRoutePattern(route.Path, r.appConfig),
+			handler: handler,
+		})
+	}
+
+	for _, route := range r.routes {
+		if _, ok := handlers[route.Method+" "+route.Path]; ok {
+			return nil, fmt.Errorf("%w: %s %s", ErrUnknownRoute, route.Method, route.Path)
+		}
+	}
+
+	return compiled, nil
+}
+
+// routeParamName matches the name of a route parameter, which does not affect the requests the route matches.
+var routeParamName = regexp.MustCompile(`:[A-Za-z0-9_]+`)
+
+// routesOverlap reports whether two route patterns can match the same request path.
+// Patterns of the same shape, such as "/users/:id" and "/users/:name", always overlap.
+// Other patterns with parameters or wildcards are compared against static patterns only.
+func routesOverlap(a, b string, cfg fiber.Config) bool {
+	shape := func(path string) string {
+		path = routeParamName.ReplaceAllString(path, ":")
+		if !cfg.CaseSensitive {
+			path = strings.ToLower(path)
+		}
+		if !cfg.StrictRouting && len(path) > 1 {
+			path = strings.TrimRight(path, "/")
+		}
+		return path
+	}
+	if shape(a) == shape(b) {
+		return true
+	}
+	return (isStaticRoute(b) && fiber.RoutePatternMatch(b, a, cfg)) ||
+		(isStaticRoute(a) && fiber.RoutePatternMatch(a, b, cfg))
+}
+
+// isStaticRoute reports whether a route pattern has no parameters or wildcards.
+func isStaticRoute(path string) bool {
+	return !strings.ContainsAny(path, ":*+")
+}
+
+// routePattern is a route pattern parsed once when the Registry is compiled, so that matching a request
+// does not parse the patterns of every route again.
+type routePattern struct {
+	// static is the pattern of a route without parameters or wildcards, which matches the path exactly.
+	static string
+
+	// regexp matches the paths of a route with parameters or wildcards.
+	regexp *regexp.Regexp
+
+	// fallback is the pattern of a route with parameter constraints, escaped characters, or several wildcards,
+	// which is matched by Fiber itself.
+	fallback string
+
+	cfg fiber.Config
+}
+
+// compileRoutePattern parses a route pattern the way Fiber does for the given app configuration.
+// Parameters, such as ":id", match up to the next "/" or constant part of the pattern. Optional parameters and
+// the "*" wildcard may be empty and, at the end of the pattern, also match a missing preceding "/".
+// The "+" wildcard matches one or more characters.
+func compileRoutePattern(pattern string, cfg fiber.Config) routePattern {
+	if pattern == "" || pattern[0] != '/' {
+		pattern = "/" + pattern
+	}
+	if !cfg.CaseSensitive {
+		pattern = strings.ToLower(pattern)
+	}
+	if !cfg.StrictRouting && len(pattern) > 1 {
+		pattern = strings.TrimRight(pattern, "/")
+	}
+
+	switch {
+	case pattern == "/*":
+		return routePattern{regexp: regexp.MustCompile(`^`)}
+	case strings.ContainsAny(pattern, `<\`) || strings.Count(pattern, "*")+strings.Count(pattern, "+") > 1:
+		return routePattern{fallback: pattern, cfg: cfg}
+	case isStaticRoute(pattern):
+		return routePattern{static: pattern}
+	}
+
+	var expr strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case ':':
+			end := i + 1
+			for end < len(pattern) && !strings.ContainsRune("?:/-.", rune(pattern[end])) {
+				end++
+			}
+			if end < len(pattern) && pattern[end] == '?' {
+				end++
+				writeOptional(&expr, `[^/]*?`, end == len(pattern))
+			} else {
+				expr.WriteString(`[^/]+?`)
+			}
+			i = end - 1
+		case '*':
+			writeOptional(&expr, `.*`, i == len(pattern)-1)
+		case '+':
+			expr.WriteString(`.+`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	return routePattern{regexp: regexp.MustCompile(`^` + expr.String() + `$`)}
+}
+
+// writeOptional writes an optional part of a route pattern, which may be empty. At the end of the pattern,
+// the "/" before it, if any, is optional as well.
+func writeOptional(expr *strings.Builder, part string, last bool) {
+	prefix := expr.String()
+	if last && strings.HasSuffix(prefix, "/") {
+		expr.Reset()
+		expr.WriteString(strings.TrimSuffix(prefix, "/") + `(?:/` + part + `)?`)
+		return
+	}
+	expr.WriteString(part)
+}
+
+// match reports whether the request path, in lower case unless routing is case-sensitive, matches the route pattern.
+func (p routePattern) match(path string) bool {
+	switch {
+	case p.regexp != nil:
+		return p.regexp.MatchString(path)
+	case p.fallback != "":
+		return fiber.RoutePatternMatch(path, p.fallback, p.cfg)
+	default:
+		return path == p.static
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// ruleSet is an immutable set of compiled rules, together with the options that are reloaded with them.
+type ruleSet struct {
+	rules             []Restrictor
+	names             []string
+	fields            [][]string
+	reportOnly        bool
+	enforcePercentage int
+
+	// limitsBody is true when the first rule is the MaxBodySize of the configuration,
+	// which decodes compressed request bodies within the limit.
+	limitsBody bool
+}
+
+// newRuleSet compiles the rules of a configuration. The final rules run after Config.Rules.
+func newRuleSet(cfg Config, final ...Restrictor) *ruleSet {
+	rules := effectiveRules(cfg, final...)
+	set := &ruleSet{
+		rules:             rules,
+		names:             make([]string, len(rules)),
+		fields:            make([][]string, len(rules)),
+		reportOnly:        cfg.ReportOnly,
+		enforcePercentage: cfg.EnforcePercentage,
+	}
+	if len(rules) > 0 {
+		_, set.limitsBody = rules[0].(maxBodySize)
+	}
+	// The identities of the rules are resolved once, since they are reported for every request.
+	for i, rule := range rules {
+		set.names[i] = ruleName(rule)
+		set.fields[i] = ruleFields(rule)
+	}
+	return set
+}
+
+// withFinal returns a copy of the rule set with the final rules appended.
+func (s *ruleSet) withFinal(final ...Restrictor) *ruleSet {
+	if len(final) == 0 {
+		return s
+	}
+	set := &ruleSet{
+		rules:             append(append([]Restrictor(nil), s.rules...), final...),
+		names:             append([]string(nil), s.names...),
+		fields:            append([][]string(nil), s.fields...),
+		reportOnly:        s.reportOnly,
+		enforcePercentage: s.enforcePercentage,
+		limitsBody:        s.limitsBody,
+	}
+	for _, rule := range final {
+		set.names = append(set.names, ruleName(rule))
+		set.fields = append(set.fields, ruleFields(rule))
+	}
+	return set
+}
+
+// validateConfig checks the options of a configuration that are compiled into a rule set.
+func validateConfig(cfg Config) error {
+	for i, rule := range cfg.Rules {
+		if rule == nil {
+			return fmt.Errorf("validator: rule %d is nil", i)
+		}
+	}
+	if cfg.MaxBodySize < 0 {
+		return errors.New("validator: MaxBodySize must not be negative")
+	}
+	if cfg.EnforcePercentage < 0 || cfg.EnforcePercentage > 100 {
+		return errors.New("validator: EnforcePercentage must be between 0 and 100")
+	}
+	return nil
+}
+
+// defaultWatchInterval is the interval at which Watch checks the configuration file when no positive interval is given.
+const defaultWatchInterval = 5 * time.Second
+
+// Reloader swaps the rules of a running Validator middleware at runtime. It is set as Config.Reloader,
+// and the rules of the configuration passed to New are used until the first reload.
+// A Reloader belongs to a single middleware, and New panics if it is already used by another one.
+//
+// A reload replaces the Rules, MaxBodySize, AllowedContentTypes, Transcode, JSONLimits, ReportOnly, and EnforcePercentage options at once.
+// Requests that are being validated keep the rules they started with, and new requests use the new rules.
+// A reload that fails keeps the previous rules.
+//
+// Example:
+//
+//	reloader := &validator.Reloader{
+//		OnError: func(err error) {
+//			log.Printf("validator rules not reloaded: %v", err)
+//		},
+//	}
+//	app.Use(validator.New(validator.Config{
+//		Reloader: reloader,
+//	}))
+//
+//	go reloader.Watch(ctx, "rules.yaml", 5*time.Second)
+type Reloader struct {
+	// OnError is called with the error of every reload that failed, including reloads done by Watch (optional).
+	OnError func(err error)
+
+	current atomic.Pointer[ruleSet]
+	bound   atomic.Bool
+}
+
+// Reload validates the rules of a configuration and makes them the rules of the middleware.
+// Options other than the rule options are ignored.
+func (r *Reloader) Reload(cfg Config) error {
+	if err := validateConfig(cfg); err != nil {
+		return r.fail(err)
+	}
+	r.current.Store(newRuleSet(cfg))
+	return nil
+}
+
+// ReloadFrom loads a configuration file with LoadConfig and reloads its rules.
+func (r *Reloader) ReloadFrom(reader io.Reader) error {
+	cfg, err := LoadConfig(reader)
+	if err != nil {
+		return r.fail(err)
+	}
+	return r.Reload(cfg)
+}
+
+// Watch loads the configuration file at path and reloads it whenever its modification time or size changes,
+// checking every interval. An interval of zero or less checks every 5 seconds.
+// It blocks until ctx is done, so it is usually run in its own goroutine. Errors are reported through OnError.
+func (r *Reloader) Watch(ctx context.Context, path string, interval time.Duration) {
+	var modTime time.Time
+	size := int64(-1)
+
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if info, err := os.Stat(path); err != nil {
+			r.fail(err)
+		} else if !info.ModTime().Equal(modTime) || info.Size() != size {
+			modTime, size = info.ModTime(), info.Size()
+			r.reloadFile(path)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reloadFile reloads the configuration file at path.
+func (r *Reloader) reloadFile(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		r.fail(err)
+		return
+	}
+	defer f.Close()
+
+	// ReloadFrom already reported the error.
+	_ = r.ReloadFrom(f)
+}
+
+// fail reports the error of a failed reload through OnError and returns it.
+func (r *Reloader) fail(err error) error {
+	if r.OnError != nil {
+		r.OnError(err)
+	}
+	return err
+}
+
+// init binds the Reloader to the middleware created by New and stores the rule set of its configuration,
+// unless the Reloader already has rules. It panics if the Reloader is already bound to another middleware,
+// which would otherwise silently serve the rules of the other middleware.
+func (r *Reloader) init(set *ruleSet) {
+	if !r.bound.CompareAndSwap(false, true) {
+		panic("validator: Reloader is already used by another Validator middleware")
+	}
+	r.current.CompareAndSwap(nil, set)
+}
+
+// ruleSource provides the rule set for every request, following the reloads of a Reloader.
+type ruleSource struct {
+	static   *ruleSet
+	reloader *Reloader
+	final    []Restrictor
+
+	// cache holds the last rule set of the Reloader and the same rule set with the final rules appended.
+	cache atomic.Pointer[[2]*ruleSet]
+}
+
+// newRuleSource creates the rule source of a configuration. The final rules run after Config.Rules.
+func newRuleSource(cfg Config, final ...Restrictor) *ruleSource {
+	if cfg.Reloader == nil {
+		return &ruleSource{static: newRuleSet(cfg, final...)}
+	}
+	cfg.Reloader.init(newRuleSet(cfg))
+	return &ruleSource{reloader: cfg.Reloader, final: final}
+}
+
+// load returns the rule set for a request.
+func (s *ruleSource) load() *ruleSet {
+	if s.reloader == nil {
+		return s.static
+	}
+
+	base := s.reloader.current.Load()
+	if len(s.final) == 0 {
+		return base
+	}
+	if cached := s.cache.Load(); cached != nil && cached[0] == base {
+		return cached[1]
+	}
+	set := base.withFinal(s.final...)
+	s.cache.Store(&[2]*ruleSet{base, set})
+	return set
+}
@@ -7,18 +7,87 @@ package validator
 import "github.com/gofiber/fiber/v2"
 
 // restrictByContentType is a helper function that determines the content type and calls the appropriate restrict function.
-func restrictByContentType(c *fiber.Ctx, restrictJSON, restrictXML, restrictOther func(c *fiber.Ctx) error) error {
-	contentType := c.Get(fiber.HeaderContentType)
-	switch contentType {
-	case fiber.MIMEApplicationJSON,
-		fiber.MIMEApplicationJSONCharsetUTF8:
-		return restrictJSON(c)
-	case fiber.MIMEApplicationXML,
-		fiber.MIMEApplicationXMLCharsetUTF8,
-		fiber.MIMETextXML,
-		fiber.MIMETextXMLCharsetUTF8:
+// JSON, MessagePack, CBOR, YAML, and TOML request bodies are decoded into the same document model before restrictDocument is called.
+// Request bodies of other content types are scanned as text by restrictOther, unless the accepted media types are
+// restricted by the rule or by Config.AllowedContentTypes without legacy text scan mode, in which case they are rejected with a 415 status.
+func restrictByContentType(c *fiber.Ctx, textScan bool, allowed []string, restrictDocument func(c *fiber.Ctx, body map[string]interface{}) error,
+	restrictXML, restrictOther func(c *fiber.Ctx) error) error {
+	switch format := bodyFormatOf(c); format {
+	case formatXML:
 		return restrictXML(c)
+	case formatOther:
+		if legacyTextScan(c, textScan, allowed) {
+			return restrictOther(c)
+		}
+		if len(c.Body()) == 0 {
+			return nil
+		}
+		return errUnsupportedMediaType(requestMediaType(c))
+	case formatNDJSON:
+		// The middleware evaluates the rule for every line of an NDJSON request body as a JSON request body.
+		return nil
 	default:
-		return restrictOther(c)
+		body, err := decodeDocument(c, format)
+		if err != nil {
+			return err
+		}
+		return restrictDocument(c, body)
 	}
 }
+
+// maxBodySize is the Restrictor used for Config.MaxBodySize.
+type maxBodySize int
+
+// Restrict implements the Restrictor interface for maxBodySize.
+func (m maxBodySize) Restrict(c *fiber.Ctx) error {
+	return restrictBodySize(c, int(m))
+}
+
+// requestLevel implements the requestRestrictor interface for maxBodySize.
+func (m maxBodySize) requestLevel() {}
+
+// Name implements the NamedRestrictor interface for maxBodySize.
+func (m maxBodySize) Name() string {
+	return "MaxBodySize"
+}
+
+// alwaysEnforced implements the alwaysEnforcedRestrictor interface for maxBodySize.
+func (m maxBodySize) alwaysEnforced() {}
+
+// restrictBodySize rejects the request with a 413 status when its body is larger than maxSize bytes.
+// The declared Content-Length and the raw body are checked first so that oversized requests are rejected before
+// the body is inspected, and compressed bodies are then decoded once, up to maxSize bytes.
+// A maxSize of zero or less disables the check.
+func restrictBodySize(c *fiber.Ctx, maxSize int) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	req := c.Request()
+	if req.Header.ContentLength() > maxSize || len(req.Body()) > maxSize {
+		return errBodyTooLarge(maxSize)
+	}
+	return decodeContentEncoding(c, maxSize)
+}
+
+// reportOnlyRestrictor is implemented by rules that can be switched to report-only mode individually.
+type reportOnlyRestrictor interface {
+	isReportOnly() bool
+}
+
+// isReportOnly reports whether the rule itself is configured to only report violations.
+func isReportOnly(rule Restrictor) bool {
+	r, ok := rule.(reportOnlyRestrictor)
+	return ok && r.isReportOnly()
+}
+
+// alwaysEnforcedRestrictor is implemented by internal rules whose violations cannot be reported only,
+// because the request cannot be handled without them passing.
+type alwaysEnforcedRestrictor interface {
+	alwaysEnforced()
+}
+
+// isAlwaysEnforced reports whether the rule ignores report-only mode.
+func isAlwaysEnforced(rule Restrictor) bool {
+	_, ok := rule.(alwaysEnforcedRestrictor)
+	return ok
+}
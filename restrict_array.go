@@ -0,0 +1,236 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RestrictArray is a Restrictor implementation that restricts the arrays of the specified fields of the request body
+// by their number of items and the uniqueness of their items, and applies element rules to every item.
+// It applies to JSON request bodies and to the other formats that are decoded into the same document model,
+// such as MessagePack, CBOR, YAML, and TOML. Fields that are missing from the request body or null are not checked,
+// and values of other types are rejected with a TYPE_MISMATCH error.
+//
+// XML request bodies and request bodies scanned as text contain no arrays, so their fields are not checked.
+type RestrictArray struct {
+	// Fields specifies the fields to check.
+	Fields []string
+
+	// MinItems specifies the minimum number of items of the arrays (optional).
+	MinItems *int
+
+	// MaxItems specifies the maximum number of items of the arrays (optional).
+	MaxItems *int
+
+	// UniqueItems rejects arrays that contain the same value more than once (optional).
+	UniqueItems bool
+
+	// Each lists the rules that every item of the arrays must pass, such as RestrictUnicode or RestrictStringLength (optional).
+	// Every item is validated as the only field of a document, named after the field and the index of the item,
+	// such as "tags[2]". The Fields of the element rules, including rules given as pointers, are set to that name,
+	// so they can be left empty. The built-in rules validate the items of the decoded request body directly,
+	// while other rules receive every item as a JSON request body. Items are not recorded in the Result.
+	// Errors of the element rules name the item as their field and report its index in the "index" param.
+	Each []Restrictor
+
+	// MaxBodySize specifies the maximum allowed size of the request body in bytes for this rule (optional).
+	// Larger requests are rejected with a 413 status before the body is parsed.
+	MaxBodySize int
+
+	// ReportOnly reports violations of this rule without rejecting the request (optional).
+	// See Config.ReportOnly for how violations are reported.
+	ReportOnly bool
+
+	// Message overrides the error message of this rule (optional).
+	// See ErrorOverride for the available placeholders.
+	Message string
+
+	// Status overrides the HTTP status code of this rule's errors (optional).
+	Status int
+
+	// FieldOverrides overrides the error message or status code for individual fields (optional).
+	FieldOverrides map[string]ErrorOverride
+}
+
+// Restrict implements the Restrictor interface for RestrictArray.
+// It checks the arrays of the specified fields in the decoded request body.
+func (r RestrictArray) Restrict(c *fiber.Ctx) error {
+	if err := restrictBodySize(c, r.MaxBodySize); err != nil {
+		return err
+	}
+	err := restrictByContentType(c, false, nil, r.restrictDocument, r.restrictText, r.restrictText)
+	return overrideError(err, ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
+
+// isReportOnly implements the reportOnlyRestrictor interface for RestrictArray.
+func (r RestrictArray) isReportOnly() bool {
+	return r.ReportOnly
+}
+
+// restrictElement implements the elementRestrictor interface for RestrictArray.
+func (r RestrictArray) restrictElement(c *fiber.Ctx, body map[string]interface{}) error {
+	return overrideError(r.restrictDocument(c, body), ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
+
+// restrictDocument checks the arrays of the specified fields in the decoded request body.
+func (r RestrictArray) restrictDocument(c *fiber.Ctx, body map[string]interface{}) error {
+	for _, field := range r.Fields {
+		value, ok := body[field]
+		if !ok || value == nil {
+			continue
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return errTypeMismatch(field, TypeArray, true, value)
+		}
+
+		if r.MinItems != nil && len(items) < *r.MinItems {
+			return errTooFewItems(field, *r.MinItems, items)
+		}
+		if r.MaxItems != nil && len(items) > *r.MaxItems {
+			return errTooManyItems(field, *r.MaxItems, items)
+		}
+		if r.UniqueItems {
+			if i := duplicateIndex(items); i >= 0 {
+				return errDuplicateItems(field, i, items[i])
+			}
+		}
+		if err := r.restrictItems(c, field, items); err != nil {
+			return err
+		}
+		recordValue(c, field, items)
+	}
+	return nil
+}
+
+// elementRestrictor is implemented by the built-in rules that validate the items of an array directly,
+// as the only field of a decoded document, instead of reading the request body.
+type elementRestrictor interface {
+	restrictElement(c *fiber.Ctx, body map[string]interface{}) error
+}
+
+// restrictItems validates every item of an array with the element rules, in order.
+func (r RestrictArray) restrictItems(c *fiber.Ctx, field string, items []interface{}) error {
+	if len(r.Each) == 0 || len(items) == 0 {
+		return nil
+	}
+
+	// The items are not fields of the request body, so the element rules do not record them in the Result.
+	if result := ResultFrom(c); result != nil {
+		c.Locals(resultKey{}, nil)
+		defer c.Locals(resultKey{}, result)
+	}
+
+	// Other rules read the request body, so the item is swapped in as a JSON request body for them.
+	var setBody func(data []byte)
+	for i, item := range items {
+		name := fmt.Sprintf("%s[%d]", field, i)
+		element := map[string]interface{}{name: item}
+		var data []byte
+
+		for _, rule := range r.Each {
+			rule = withFields(rule, []string{name})
+			var err error
+			if e, ok := rule.(elementRestrictor); ok {
+				err = e.restrictElement(c, element)
+			} else {
+				if data == nil {
+					if data, err = json.Marshal(element); err != nil {
+						return errInvalidBody(err)
+					}
+				}
+				if setBody == nil {
+					setBody = swapJSONBody(c)
+					defer setBody(nil)
+				}
+				setBody(data)
+				err = rule.Restrict(c)
+			}
+			if err != nil {
+				return elementError(err, i)
+			}
+		}
+	}
+	return nil
+}
+
+// restrictText accepts XML request bodies and request bodies scanned as text, which contain no arrays.
+func (r RestrictArray) restrictText(c *fiber.Ctx) error {
+	return nil
+}
+
+// describeSchema implements the schemaRule interface for RestrictArray.
+func (r RestrictArray) describeSchema(b *schemaBuilder) {
+	b.constrain(r.Fields, "type", string(TypeArray))
+	if r.MinItems != nil {
+		b.constrain(r.Fields, "minItems", *r.MinItems)
+	}
+	if r.MaxItems != nil {
+		b.constrain(r.Fields, "maxItems", *r.MaxItems)
+	}
+	if r.UniqueItems {
+		b.constrain(r.Fields, "uniqueItems", true)
+	}
+
+	// The element rules are described as the schema of a single field, which becomes the items schema.
+	const name = "items"
+	each := make([]Restrictor, len(r.Each))
+	for i, rule := range r.Each {
+		each[i] = withFields(rule, []string{name})
+	}
+	if properties, ok := describeRules(each)["properties"].(map[string]interface{}); ok {
+		b.constrain(r.Fields, "items", properties[name])
+	}
+}
+
+// withFields returns a copy of a rule whose Fields are replaced by the given fields,
+// or the rule itself if it has no Fields of type []string. Pointers to rules are copied as pointers to the copy.
+func withFields(rule Restrictor, fields []string) Restrictor {
+	v := reflect.ValueOf(rule)
+	pointer := v.Kind() == reflect.Pointer && !v.IsNil()
+	if pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return rule
+	}
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	f := cp.FieldByName("Fields")
+	if !f.IsValid() || !f.CanSet() || f.Type() != reflect.TypeOf(fields) {
+		return rule
+	}
+	f.Set(reflect.ValueOf(fields))
+	if pointer {
+		return cp.Addr().Interface().(Restrictor)
+	}
+	return cp.Interface().(Restrictor)
+}
+
+// elementError adds the index of an array item to the *Error of an element rule, which may be wrapped.
+// Errors of nested arrays keep the index of the innermost item, whose field names it in full.
+func elementError(err error, index int) error {
+	var e *Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	if _, ok := e.Params["index"]; ok {
+		return err
+	}
+
+	params := make(map[string]interface{}, len(e.Params)+1)
+	for k, v := range e.Params {
+		params[k] = v
+	}
+	params["index"] = index
+	e.Params = params
+	return err
+}
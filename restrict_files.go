@@ -0,0 +1,267 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sniffLength is the number of bytes that net/http.DetectContentType considers.
+const sniffLength = 512
+
+// FileSignature identifies the content type of files by a sequence of bytes at a fixed position of their content,
+// for file types that net/http.DetectContentType does not recognize.
+type FileSignature struct {
+	// ContentType is the media type of the files with the signature, such as "image/heic".
+	ContentType string
+
+	// Offset is the position of the signature in the file content.
+	// Signatures with a negative Offset are ignored.
+	Offset int
+
+	// Magic is the signature.
+	Magic []byte
+}
+
+// RestrictFiles is a Restrictor implementation that restricts the files uploaded in multipart/form-data request bodies.
+// Request bodies of other content types contain no files and are not checked.
+//
+// The content type of a file is determined by sniffing its content with the Signatures and net/http.DetectContentType,
+// never from the Content-Type declared by the client. Files whose names contain path separators, NUL bytes,
+// or Unicode bidirectional control characters are always rejected.
+type RestrictFiles struct {
+	// Fields specifies the multipart form fields of the files to check (optional).
+	// Default: every file field of the request body.
+	Fields []string
+
+	// MaxFiles specifies the maximum number of files per field (optional).
+	MaxFiles int
+
+	// MaxFileSize specifies the maximum size of every file in bytes (optional).
+	// Larger files are rejected with a 413 status.
+	MaxFileSize int
+
+	// MaxTotalSize specifies the maximum size of the files of the checked fields together in bytes (optional).
+	// Larger uploads are rejected with a 413 status.
+	MaxTotalSize int
+
+	// AllowedExtensions lists the accepted file name extensions, such as ".png" or ".tar.gz" (optional).
+	// Extensions are compared case-insensitively, and the leading dot may be omitted.
+	AllowedExtensions []string
+
+	// AllowedFileTypes lists the accepted content types, or media ranges such as "image/*", of the files as sniffed
+	// from their content (optional). Files of other types are rejected with a 415 status.
+	AllowedFileTypes []string
+
+	// Signatures are consulted before net/http.DetectContentType to sniff the content type of the files (optional).
+	// The first matching signature determines the content type.
+	Signatures []FileSignature
+
+	// MaxBodySize specifies the maximum allowed size of the request body in bytes for this rule (optional).
+	// Larger requests are rejected with a 413 status before the body is parsed.
+	MaxBodySize int
+
+	// ReportOnly reports violations of this rule without rejecting the request (optional).
+	// See Config.ReportOnly for how violations are reported.
+	ReportOnly bool
+
+	// Message overrides the error message of this rule (optional).
+	// See ErrorOverride for the available placeholders.
+	Message string
+
+	// Status overrides the HTTP status code of this rule's errors (optional).
+	Status int
+
+	// FieldOverrides overrides the error message or status code for individual fields (optional).
+	FieldOverrides map[string]ErrorOverride
+}
+
+// Restrict implements the Restrictor interface for RestrictFiles.
+// It checks the files of the specified fields in a multipart/form-data request body.
+func (r RestrictFiles) Restrict(c *fiber.Ctx) error {
+	if err := restrictBodySize(c, r.MaxBodySize); err != nil {
+		return err
+	}
+	err := r.restrictFiles(c)
+	return overrideError(err, ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
+
+// isReportOnly implements the reportOnlyRestrictor interface for RestrictFiles.
+func (r RestrictFiles) isReportOnly() bool {
+	return r.ReportOnly
+}
+
+// restrictFiles checks the files of the specified fields, in order.
+func (r RestrictFiles) restrictFiles(c *fiber.Ctx) error {
+	fields, uploads, err := uploadedFiles(c, r.Fields)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, field := range fields {
+		files := uploads[field]
+		if len(files) == 0 {
+			continue
+		}
+		if r.MaxFiles > 0 && len(files) > r.MaxFiles {
+			return errTooManyFiles(field, r.MaxFiles)
+		}
+
+		names := make([]string, len(files))
+		for i, file := range files {
+			name, err := r.checkFile(field, file)
+			if err != nil {
+				return err
+			}
+			names[i] = name
+
+			total += int(file.Size)
+			if r.MaxTotalSize > 0 && total > r.MaxTotalSize {
+				return errFilesTooLarge(r.MaxTotalSize)
+			}
+		}
+		recordValue(c, field, names)
+	}
+	return nil
+}
+
+// uploadedFiles returns the files of a multipart/form-data request body by field, and the fields to check:
+// the given fields, or every file field in name order if none are given. Request bodies of other content types have no files.
+func uploadedFiles(c *fiber.Ctx, fields []string) ([]string, map[string][]*multipart.FileHeader, error) {
+	if requestMediaType(c) != fiber.MIMEMultipartForm || len(c.Body()) == 0 {
+		return nil, nil, nil
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, nil, errInvalidBody(err)
+	}
+
+	if len(fields) == 0 {
+		fields = make([]string, 0, len(form.File))
+		for field := range form.File {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+	}
+	return fields, form.File, nil
+}
+
+// checkFile checks the name, size, and content type of a file, and returns its name.
+func (r RestrictFiles) checkFile(field string, file *multipart.FileHeader) (string, error) {
+	name := uploadedFilename(file)
+	if !validFilename(name) {
+		return "", errInvalidFilename(field)
+	}
+	if len(r.AllowedExtensions) > 0 && !r.allowsExtension(name) {
+		return "", errFileExtensionNotAllowed(field, name)
+	}
+	if r.MaxFileSize > 0 && file.Size > int64(r.MaxFileSize) {
+		return "", errFileTooLarge(field, name, r.MaxFileSize)
+	}
+	if len(r.AllowedFileTypes) > 0 {
+		contentType, err := r.sniff(file)
+		if err != nil {
+			return "", errInvalidBody(err)
+		}
+		if !r.allowsFileType(contentType) {
+			return "", errFileTypeNotAllowed(field, name, contentType)
+		}
+	}
+	return name, nil
+}
+
+// uploadedFilename returns the file name as sent by the client. The FileHeader only keeps the last element
+// of the name, which hides path separators, so the name is taken from the Content-Disposition header of the part.
+func uploadedFilename(file *multipart.FileHeader) string {
+	_, params, err := mime.ParseMediaType(file.Header.Get(fiber.HeaderContentDisposition))
+	if name, ok := params["filename"]; err == nil && ok {
+		return name
+	}
+	return file.Filename
+}
+
+// validFilename reports whether a file name contains no path separators, NUL bytes, or Unicode bidirectional
+// control characters, which can be used to write outside the upload directory or to disguise the extension.
+func validFilename(name string) bool {
+	for _, r := range name {
+		switch r {
+		case '/', '\\', 0,
+			'\u061c', '\u200e', '\u200f',
+			'\u202a', '\u202b', '\u202c', '\u202d', '\u202e',
+			'\u2066', '\u2067', '\u2068', '\u2069':
+			return false
+		}
+	}
+	return true
+}
+
+// allowsExtension reports whether the file name ends with one of the allowed extensions.
+func (r RestrictFiles) allowsExtension(name string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range r.AllowedExtensions {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if strings.HasSuffix(name, ext) && len(name) > len(ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsFileType reports whether the sniffed content type matches one of the allowed file types.
+func (r RestrictFiles) allowsFileType(contentType string) bool {
+	for _, pattern := range r.AllowedFileTypes {
+		if mediaTypeMatches(pattern, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// sniff determines the content type of a file from the start of its content, without its parameters.
+func (r RestrictFiles) sniff(file *multipart.FileHeader) (string, error) {
+	length := sniffLength
+	for _, sig := range r.Signatures {
+		if end := sig.Offset + len(sig.Magic); sig.Offset >= 0 && end > length {
+			length = end
+		}
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	data := make([]byte, length)
+	n, err := io.ReadFull(f, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	data = data[:n]
+
+	for _, sig := range r.Signatures {
+		if end := sig.Offset + len(sig.Magic); sig.Offset >= 0 && len(sig.Magic) > 0 && end <= len(data) && bytes.Equal(data[sig.Offset:end], sig.Magic) {
+			return strings.ToLower(sig.ContentType), nil
+		}
+	}
+
+	contentType := http.DetectContentType(data)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType, nil
+	}
+	return contentType, nil
+}
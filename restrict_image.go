@@ -0,0 +1,277 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif"  // register the GIF format for image.DecodeConfig
+	_ "image/jpeg" // register the JPEG format for image.DecodeConfig
+	_ "image/png"  // register the PNG format for image.DecodeConfig
+	"io"
+	"mime/multipart"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RestrictImage is a Restrictor implementation that restricts the images uploaded in multipart/form-data request bodies
+// by their format and pixel dimensions. Request bodies of other content types contain no files and are not checked.
+//
+// Only the image headers are decoded with image.DecodeConfig, so the dimensions of an image are checked before any
+// pixel data is decompressed, which guards the handler against decompression bombs: small files that decode into huge images.
+// The PNG, JPEG, and GIF formats are supported, and further formats can be registered with image.RegisterFormat.
+type RestrictImage struct {
+	// Fields specifies the multipart form fields of the images to check (optional).
+	// Default: every file field of the request body.
+	Fields []string
+
+	// AllowedFormats lists the accepted image formats by the names that image.DecodeConfig reports,
+	// such as "png", "jpeg", or "gif" (optional). Images of other formats are rejected with a 415 status.
+	// Default: every registered image format.
+	AllowedFormats []string
+
+	// MaxWidth specifies the maximum width of the images in pixels (optional).
+	MaxWidth int
+
+	// MaxHeight specifies the maximum height of the images in pixels (optional).
+	MaxHeight int
+
+	// MaxPixels specifies the maximum number of pixels (width × height) of the images (optional).
+	MaxPixels int
+
+	// AllowAnimated accepts animated GIF and PNG (APNG) images (optional).
+	// By default, images with more than one frame are rejected.
+	AllowAnimated bool
+
+	// MaxBodySize specifies the maximum allowed size of the request body in bytes for this rule (optional).
+	// Larger requests are rejected with a 413 status before the body is parsed.
+	MaxBodySize int
+
+	// ReportOnly reports violations of this rule without rejecting the request (optional).
+	// See Config.ReportOnly for how violations are reported.
+	ReportOnly bool
+
+	// Message overrides the error message of this rule (optional).
+	// See ErrorOverride for the available placeholders.
+	Message string
+
+	// Status overrides the HTTP status code of this rule's errors (optional).
+	Status int
+
+	// FieldOverrides overrides the error message or status code for individual fields (optional).
+	FieldOverrides map[string]ErrorOverride
+}
+
+// Restrict implements the Restrictor interface for RestrictImage.
+// It checks the images of the specified fields in a multipart/form-data request body.
+func (r RestrictImage) Restrict(c *fiber.Ctx) error {
+	if err := restrictBodySize(c, r.MaxBodySize); err != nil {
+		return err
+	}
+	err := r.restrictImages(c)
+	return overrideError(err, ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
+
+// isReportOnly implements the reportOnlyRestrictor interface for RestrictImage.
+func (r RestrictImage) isReportOnly() bool {
+	return r.ReportOnly
+}
+
+// restrictImages checks the images of the specified fields, in order.
+func (r RestrictImage) restrictImages(c *fiber.Ctx) error {
+	fields, uploads, err := uploadedFiles(c, r.Fields)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		files := uploads[field]
+		if len(files) == 0 {
+			continue
+		}
+		formats := make([]string, len(files))
+		for i, file := range files {
+			format, err := r.checkImage(field, file)
+			if err != nil {
+				return err
+			}
+			formats[i] = format
+		}
+		recordValue(c, field, formats)
+	}
+	return nil
+}
+
+// checkImage checks the format, dimensions, and frames of an image, and returns its format.
+func (r RestrictImage) checkImage(field string, file *multipart.FileHeader) (string, error) {
+	name := uploadedFilename(file)
+	f, err := file.Open()
+	if err != nil {
+		return "", errInvalidBody(err)
+	}
+	defer f.Close()
+
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return "", errInvalidImage(field, name)
+	}
+	if !r.allowsFormat(format) {
+		return "", errImageFormatNotAllowed(field, name, format)
+	}
+	if r.MaxWidth > 0 && cfg.Width > r.MaxWidth {
+		return "", errImageWidthExceeded(field, name, cfg.Width, r.MaxWidth)
+	}
+	if r.MaxHeight > 0 && cfg.Height > r.MaxHeight {
+		return "", errImageHeightExceeded(field, name, cfg.Height, r.MaxHeight)
+	}
+	if pixels := int64(cfg.Width) * int64(cfg.Height); r.MaxPixels > 0 && pixels > int64(r.MaxPixels) {
+		return "", errImagePixelsExceeded(field, name, pixels, r.MaxPixels)
+	}
+
+	if !r.AllowAnimated && (format == "gif" || format == "png") {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return "", errInvalidBody(err)
+		}
+		animated, err := isAnimated(bufio.NewReader(f), format)
+		if err != nil {
+			return "", errInvalidImage(field, name)
+		}
+		if animated {
+			return "", errImageAnimated(field, name)
+		}
+	}
+	return format, nil
+}
+
+// allowsFormat reports whether the image format is accepted.
+func (r RestrictImage) allowsFormat(format string) bool {
+	if len(r.AllowedFormats) == 0 {
+		return true
+	}
+	for _, allowed := range r.AllowedFormats {
+		if allowed == format {
+			return true
+		}
+	}
+	return false
+}
+
+// isAnimated reports whether a GIF image has more than one frame or a PNG image is an APNG image.
+// Only the structure of the image is read; no pixel data is decompressed.
+func isAnimated(r *bufio.Reader, format string) (bool, error) {
+	if format == "gif" {
+		return gifAnimated(r)
+	}
+	return pngAnimated(r)
+}
+
+// pngAnimated reports whether a PNG image contains an animation control chunk, which precedes the image data of APNG images.
+func pngAnimated(r *bufio.Reader) (bool, error) {
+	if _, err := r.Discard(8); err != nil {
+		return false, err
+	}
+	var header [8]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return false, err
+		}
+		switch string(header[4:]) {
+		case "acTL":
+			return true, nil
+		case "IDAT", "IEND":
+			return false, nil
+		}
+		// The chunk data is followed by its CRC.
+		if _, err := r.Discard(int(binary.BigEndian.Uint32(header[:4])) + 4); err != nil {
+			return false, err
+		}
+	}
+}
+
+// errGIFStructure reports a GIF image whose blocks cannot be read.
+var errGIFStructure = errors.New("invalid GIF structure")
+
+// gifAnimated reports whether a GIF image contains more than one image descriptor.
+func gifAnimated(r *bufio.Reader) (bool, error) {
+	// The header is followed by the logical screen descriptor and the optional global color table.
+	var header [13]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return false, err
+	}
+	if err := skipColorTable(r, header[10]); err != nil {
+		return false, err
+	}
+
+	frames := 0
+	for {
+		introducer, err := r.ReadByte()
+		if err == io.EOF {
+			// Some encoders omit the trailer.
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch introducer {
+		case 0x21: // extension
+			if _, err := r.ReadByte(); err != nil {
+				return false, err
+			}
+			if err := skipSubBlocks(r); err != nil {
+				return false, err
+			}
+		case 0x2C: // image descriptor
+			frames++
+			if frames > 1 {
+				return true, nil
+			}
+			var descriptor [9]byte
+			if _, err := io.ReadFull(r, descriptor[:]); err != nil {
+				return false, err
+			}
+			if err := skipColorTable(r, descriptor[8]); err != nil {
+				return false, err
+			}
+			// The LZW minimum code size precedes the image data.
+			if _, err := r.ReadByte(); err != nil {
+				return false, err
+			}
+			if err := skipSubBlocks(r); err != nil {
+				return false, err
+			}
+		case 0x3B: // trailer
+			return false, nil
+		default:
+			return false, errGIFStructure
+		}
+	}
+}
+
+// skipColorTable skips the color table that the packed fields of a GIF descriptor announce.
+func skipColorTable(r *bufio.Reader, packed byte) error {
+	if packed&0x80 == 0 {
+		return nil
+	}
+	_, err := r.Discard(3 << (packed&0x07 + 1))
+	return err
+}
+
+// skipSubBlocks skips a sequence of GIF data sub-blocks up to its terminator.
+func skipSubBlocks(r *bufio.Reader) error {
+	for {
+		size, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if size == 0 {
+			return nil
+		}
+		if _, err := r.Discard(int(size)); err != nil {
+			return err
+		}
+	}
+}
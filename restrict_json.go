@@ -0,0 +1,204 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JSONLimits is a Restrictor implementation that token-scans a JSON request body and rejects documents
+// that are ambiguous or too large before any rule decodes them.
+//
+// Note: c.BodyParser into a map silently keeps the last value of a duplicated key, so without this check
+// the validator and the handler may see different values for the same field.
+type JSONLimits struct {
+	// MaxDepth specifies the maximum nesting depth of objects and arrays (optional).
+	MaxDepth *int
+
+	// MaxKeys specifies the maximum number of keys allowed in a single object (optional).
+	MaxKeys *int
+
+	// MaxArrayLength specifies the maximum number of elements allowed in a single array (optional).
+	MaxArrayLength *int
+
+	// MaxStringLength specifies the maximum length in bytes of any string, including object keys (optional).
+	MaxStringLength *int
+
+	// AllowDuplicateKeys disables the duplicate key check.
+	//
+	// Optional. Default: false
+	AllowDuplicateKeys bool
+
+	// CaseInsensitiveKeys treats keys that differ only in case as duplicates,
+	// matching how encoding/json assigns keys to struct fields.
+	//
+	// Optional. Default: false
+	CaseInsensitiveKeys bool
+}
+
+// jsonFrame tracks the state of an object or array that is currently open while scanning.
+type jsonFrame struct {
+	object    bool
+	expectKey bool
+	count     int
+	keys      map[string]struct{}
+}
+
+// Restrict implements the Restrictor interface for JSONLimits.
+// It only inspects JSON request bodies; other content types are left to the remaining rules.
+func (r JSONLimits) Restrict(c *fiber.Ctx) error {
+	if bodyFormatOf(c) != formatJSON {
+		return nil
+	}
+	return r.scan(c.Body())
+}
+
+// alwaysEnforced implements the alwaysEnforcedRestrictor interface for JSONLimits.
+// Ambiguous or oversized documents would otherwise reach the remaining rules and the handler.
+func (r JSONLimits) alwaysEnforced() {}
+
+// scan walks the tokens of a JSON document without decoding it into memory.
+func (r JSONLimits) scan(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var stack []*jsonFrame
+	seenValue := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errInvalidJSONBody(err)
+		}
+
+		// Object keys are reported as plain strings, so they must be told apart from string values.
+		if top := topFrame(stack); top != nil && top.object && top.expectKey {
+			if d, ok := tok.(json.Delim); ok && d == '}' {
+				stack = stack[:len(stack)-1]
+				r.endValue(stack)
+				continue
+			}
+			if err := r.checkKey(top, tok.(string)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(stack) == 0 {
+			if seenValue {
+				return errInvalidJSONBody(errors.New("unexpected data after top-level value"))
+			}
+			seenValue = true
+		}
+
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{', '[':
+				if err := r.startValue(stack); err != nil {
+					return err
+				}
+				if r.MaxDepth != nil && len(stack) >= *r.MaxDepth {
+					return errJSONLimitExceeded(CodeJSONMaxDepthExceeded, ErrJSONExceedsMaximumDepth, *r.MaxDepth)
+				}
+				frame := &jsonFrame{object: v == '{', expectKey: v == '{'}
+				if frame.object && !r.AllowDuplicateKeys {
+					frame.keys = make(map[string]struct{})
+				}
+				stack = append(stack, frame)
+			default:
+				stack = stack[:len(stack)-1]
+				r.endValue(stack)
+			}
+		case string:
+			if err := r.startValue(stack); err != nil {
+				return err
+			}
+			if err := r.checkString(v); err != nil {
+				return err
+			}
+			r.endValue(stack)
+		default:
+			if err := r.startValue(stack); err != nil {
+				return err
+			}
+			r.endValue(stack)
+		}
+	}
+
+	if len(stack) > 0 {
+		return errInvalidJSONBody(io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
+
+// checkKey enforces the key count, key length, and duplicate key limits for an object key.
+func (r JSONLimits) checkKey(top *jsonFrame, key string) error {
+	if err := r.checkString(key); err != nil {
+		return err
+	}
+
+	top.count++
+	if r.MaxKeys != nil && top.count > *r.MaxKeys {
+		return errJSONLimitExceeded(CodeJSONMaxKeysExceeded, ErrJSONObjectExceedsMaximumKeys, *r.MaxKeys)
+	}
+
+	if top.keys != nil {
+		name := key
+		if r.CaseInsensitiveKeys {
+			name = strings.ToLower(key)
+		}
+		if _, ok := top.keys[name]; ok {
+			return errDuplicateJSONKey(key)
+		}
+		top.keys[name] = struct{}{}
+	}
+
+	top.expectKey = false
+	return nil
+}
+
+// checkString enforces the maximum string length.
+func (r JSONLimits) checkString(str string) error {
+	if r.MaxStringLength != nil && len(str) > *r.MaxStringLength {
+		return errJSONLimitExceeded(CodeJSONMaxStringLengthExceeded, ErrJSONStringExceedsMaximumLength, *r.MaxStringLength)
+	}
+	return nil
+}
+
+// startValue counts a new value against the array length limit of the enclosing array.
+func (r JSONLimits) startValue(stack []*jsonFrame) error {
+	if top := topFrame(stack); top != nil && !top.object {
+		top.count++
+		if r.MaxArrayLength != nil && top.count > *r.MaxArrayLength {
+			return errJSONLimitExceeded(CodeJSONMaxArrayLengthExceeded, ErrJSONArrayExceedsMaximumLength, *r.MaxArrayLength)
+		}
+	}
+	return nil
+}
+
+// endValue marks the enclosing object as waiting for its next key.
+func (r JSONLimits) endValue(stack []*jsonFrame) {
+	if top := topFrame(stack); top != nil && top.object {
+		top.expectKey = true
+	}
+}
+
+// topFrame returns the innermost open object or array, or nil at the top level.
+func topFrame(stack []*jsonFrame) *jsonFrame {
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
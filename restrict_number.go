@@ -6,10 +6,8 @@ package validator
 
 import (
 	"encoding/xml"
-	"fmt"
 	"reflect"
 	"strconv"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/text/cases"
@@ -27,21 +25,61 @@ type RestrictNumberOnly struct {
 
 	// MaxDigits specifies the maximum number of digits allowed in the field value (optional).
 	MaxDigits *int
+
+	// MaxBodySize specifies the maximum allowed size of the request body in bytes for this rule (optional).
+	// Larger requests are rejected with a 413 status before the body is parsed.
+	MaxBodySize int
+
+	// AllowedContentTypes lists the media types, or media ranges such as "application/*", of the request bodies
+	// accepted by this rule (optional). Request bodies of other media types are rejected with a 415 status,
+	// and accepted request bodies of content types that are not decoded are rejected unless LegacyTextScan is set.
+	AllowedContentTypes []string
+
+	// LegacyTextScan keeps scanning request bodies of content types other than JSON, XML, and the other decoded formats
+	// for the fields as text when AllowedContentTypes or Config.AllowedContentTypes is set (optional).
+	// See Config.LegacyTextScan.
+	LegacyTextScan bool
+
+	// ReportOnly reports violations of this rule without rejecting the request (optional).
+	// See Config.ReportOnly for how violations are reported.
+	ReportOnly bool
+
+	// Message overrides the error message of this rule (optional).
+	// See ErrorOverride for the available placeholders.
+	Message string
+
+	// Status overrides the HTTP status code of this rule's errors (optional).
+	Status int
+
+	// FieldOverrides overrides the error message or status code for individual fields (optional).
+	FieldOverrides map[string]ErrorOverride
 }
 
 // Restrict implements the Restrictor interface for RestrictNumberOnly.
 // It checks the specified fields in the request body for numeric values and maximum limit based on the content type.
 func (r RestrictNumberOnly) Restrict(c *fiber.Ctx) error {
-	return restrictByContentType(c, r.restrictJSON, r.restrictXML, r.restrictOther)
+	if err := restrictBodySize(c, r.MaxBodySize); err != nil {
+		return err
+	}
+	if err := restrictContentType(c, r.AllowedContentTypes); err != nil {
+		return err
+	}
+	err := restrictByContentType(c, r.LegacyTextScan, r.AllowedContentTypes, r.restrictDocument, r.restrictXML, r.restrictOther)
+	return overrideError(err, ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
 }
 
-// restrictJSON checks the specified fields in the JSON request body for numeric values and maximum limit.
-func (r RestrictNumberOnly) restrictJSON(c *fiber.Ctx) error {
-	var body map[string]interface{}
-	if err := c.BodyParser(&body); err != nil {
-		return NewError(fiber.StatusBadRequest, ErrInvalidJSONBody)
-	}
+// isReportOnly implements the reportOnlyRestrictor interface for RestrictNumberOnly.
+func (r RestrictNumberOnly) isReportOnly() bool {
+	return r.ReportOnly
+}
+
+// restrictElement implements the elementRestrictor interface for RestrictNumberOnly.
+func (r RestrictNumberOnly) restrictElement(c *fiber.Ctx, body map[string]interface{}) error {
+	return overrideError(r.restrictDocument(c, body), ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
 
+// restrictDocument checks the specified fields in the decoded request body for numeric values and maximum limit.
+func (r RestrictNumberOnly) restrictDocument(c *fiber.Ctx, body map[string]interface{}) error {
 	var invalidFields []string
 	for _, field := range r.Fields {
 		value, ok := body[field]
@@ -64,16 +102,17 @@ func (r RestrictNumberOnly) restrictJSON(c *fiber.Ctx) error {
 				continue
 			}
 			if r.MaxDigits != nil && len(numStr) > *r.MaxDigits {
-				return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumDigits, field, *r.MaxDigits))
+				return errMaxDigitsExceeded(field, value, *r.MaxDigits)
 			}
 			if r.Max != nil && num > *r.Max {
-				return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumValue, field, *r.Max))
+				return errMaxValueExceeded(field, value, *r.Max)
 			}
+			recordValue(c, field, value)
 		}
 	}
 
 	if len(invalidFields) > 0 {
-		return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldMustContainNumbersOnly, strings.Join(invalidFields, "', '")))
+		return errNumberOnly(invalidFields)
 	}
 
 	return nil
@@ -94,7 +133,7 @@ func (r RestrictNumberOnly) restrictXML(c *fiber.Ctx) error {
 	bodyValue := reflect.New(bodyType).Elem()
 
 	if err := xml.Unmarshal(c.Body(), bodyValue.Addr().Interface()); err != nil {
-		return NewError(fiber.StatusBadRequest, ErrInvalidXMLBody)
+		return errInvalidXMLBody(err)
 	}
 
 	var invalidFields []string
@@ -105,16 +144,17 @@ func (r RestrictNumberOnly) restrictXML(c *fiber.Ctx) error {
 		} else {
 			num, _ := strconv.Atoi(value)
 			if r.MaxDigits != nil && len(value) > *r.MaxDigits {
-				return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumDigits, field, *r.MaxDigits))
+				return errMaxDigitsExceeded(field, value, *r.MaxDigits)
 			}
 			if r.Max != nil && num > *r.Max {
-				return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumValue, field, *r.Max))
+				return errMaxValueExceeded(field, value, *r.Max)
 			}
+			recordValue(c, field, value)
 		}
 	}
 
 	if len(invalidFields) > 0 {
-		return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldMustContainNumbersOnly, strings.Join(invalidFields, "', '")))
+		return errNumberOnly(invalidFields)
 	}
 
 	return nil
@@ -132,16 +172,17 @@ func (r RestrictNumberOnly) restrictOther(c *fiber.Ctx) error {
 		} else {
 			num, _ := strconv.Atoi(fieldValue)
 			if r.MaxDigits != nil && len(fieldValue) > *r.MaxDigits {
-				return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumDigits, field, *r.MaxDigits))
+				return errMaxDigitsExceeded(field, fieldValue, *r.MaxDigits)
 			}
 			if r.Max != nil && num > *r.Max {
-				return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumValue, field, *r.Max))
+				return errMaxValueExceeded(field, fieldValue, *r.Max)
 			}
+			recordValue(c, field, fieldValue)
 		}
 	}
 
 	if len(invalidFields) > 0 {
-		return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldMustContainNumbersOnly, strings.Join(invalidFields, "', '")))
+		return errNumberOnly(invalidFields)
 	}
 
 	return nil
@@ -0,0 +1,509 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// RestrictOpenAPI is a Restrictor implementation that validates requests against the operations of an
+// OpenAPI 3.0 or 3.1 specification. It is created with LoadOpenAPI.
+//
+// The request is matched to an operation by its method and path, and its path, query, header, and cookie parameters
+// and its JSON or form request body are validated against the declared schemas. Violations are reported as *Error
+// with a JSON Pointer to the offending value in the Field, such as "/body/address/zip" or "/query/limit".
+//
+// Note: Only local references such as "#/components/schemas/User" are supported, so the specification never causes
+// network or file system access while it is loaded or used.
+type RestrictOpenAPI struct {
+	// BasePath is the path prefix of the API, such as the path of the server URL, that is removed from
+	// the request path before it is matched to the paths of the specification (optional).
+	BasePath string
+
+	// AllowUnknownOperations passes requests that do not match an operation of the specification,
+	// instead of rejecting them with a 404 or 405 status.
+	//
+	// Optional. Default: false
+	AllowUnknownOperations bool
+
+	paths []*openAPIPath
+}
+
+// openAPIPath is a compiled path of the specification.
+type openAPIPath struct {
+	template   string
+	re         *regexp.Regexp
+	names      []string
+	operations map[string]*openAPIOperation
+}
+
+// openAPIOperation is a compiled operation of the specification.
+type openAPIOperation struct {
+	params []*openAPIParam
+	body   *openAPIBody
+}
+
+// openAPIParam is a compiled parameter of an operation.
+type openAPIParam struct {
+	name     string
+	in       string
+	required bool
+	schema   *schema
+}
+
+// openAPIBody is a compiled request body of an operation.
+type openAPIBody struct {
+	required bool
+	content  map[string]*schema
+}
+
+// openAPIMethods are the operations of a path item.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// LoadOpenAPI reads an OpenAPI 3.0 or 3.1 specification in YAML or JSON and creates a RestrictOpenAPI for it.
+// Specifications with remote references, invalid schemas, or invalid patterns are rejected.
+// As required by OpenAPI, header parameters named Accept, Content-Type, or Authorization are ignored.
+//
+// Example:
+//
+//	f, err := os.Open("openapi.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	spec, err := validator.LoadOpenAPI(f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	app.Use(validator.New(validator.Config{
+//		Rules: []validator.Restrictor{spec},
+//	}))
+func LoadOpenAPI(r io.Reader) (*RestrictOpenAPI, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(jsonTabsToSpaces(data), &raw); err != nil {
+		return nil, fmt.Errorf("validator: %w", err)
+	}
+	normalized, err := normalizeDocument(raw)
+	if err != nil {
+		return nil, fmt.Errorf("validator: %w", err)
+	}
+	doc, ok := normalized.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("validator: OpenAPI specification must be an object")
+	}
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, errors.New("validator: only OpenAPI 3.0 and 3.1 specifications are supported")
+	}
+	if err := checkLocalRefs(doc); err != nil {
+		return nil, fmt.Errorf("validator: %w", err)
+	}
+
+	compiler := &schemaCompiler{doc: doc, refs: make(map[string]*schema)}
+	paths, _ := doc["paths"].(map[string]interface{})
+	spec := &RestrictOpenAPI{}
+	for template, item := range paths {
+		path, err := compileOpenAPIPath(compiler, template, item)
+		if err != nil {
+			return nil, fmt.Errorf("validator: paths/%s: %w", escapePointer(template), err)
+		}
+		spec.paths = append(spec.paths, path)
+	}
+
+	// Concrete paths are matched before templated paths, as required by the specification.
+	sort.Slice(spec.paths, func(i, j int) bool {
+		a, b := spec.paths[i], spec.paths[j]
+		if len(a.names) != len(b.names) {
+			return len(a.names) < len(b.names)
+		}
+		return a.template < b.template
+	})
+
+	return spec, nil
+}
+
+// compileOpenAPIPath compiles a path item of the specification.
+func compileOpenAPIPath(compiler *schemaCompiler, template string, item interface{}) (*openAPIPath, error) {
+	m, err := deref(compiler.doc, item)
+	if err != nil {
+		return nil, err
+	}
+
+	path := &openAPIPath{template: template, operations: make(map[string]*openAPIOperation)}
+
+	// Every template expression matches a single, non-empty path segment.
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	rest := template
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, errors.New("unterminated path template expression")
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:start]))
+		pattern.WriteString("([^/]+)")
+		path.names = append(path.names, rest[start+1:start+end])
+		rest = rest[start+end+1:]
+	}
+	pattern.WriteString(regexp.QuoteMeta(rest))
+	pattern.WriteString("$")
+	path.re = regexp.MustCompile(pattern.String())
+
+	common, err := compileOpenAPIParams(compiler, m["parameters"])
+	if err != nil {
+		return nil, fmt.Errorf("parameters: %w", err)
+	}
+
+	for _, method := range openAPIMethods {
+		op, ok := m[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		operation := &openAPIOperation{}
+
+		params, err := compileOpenAPIParams(compiler, op["parameters"])
+		if err != nil {
+			return nil, fmt.Errorf("%s/parameters: %w", method, err)
+		}
+		// Parameters of the operation override the parameters of the path with the same name and location.
+		for _, p := range common {
+			overridden := false
+			for _, q := range params {
+				if p.in == q.in && strings.EqualFold(p.name, q.name) {
+					overridden = true
+					break
+				}
+			}
+			if !overridden {
+				operation.params = append(operation.params, p)
+			}
+		}
+		operation.params = append(operation.params, params...)
+
+		if body, ok := op["requestBody"]; ok {
+			if operation.body, err = compileOpenAPIBody(compiler, body); err != nil {
+				return nil, fmt.Errorf("%s/requestBody: %w", method, err)
+			}
+		}
+
+		path.operations[strings.ToUpper(method)] = operation
+	}
+
+	return path, nil
+}
+
+// compileOpenAPIParams compiles a list of parameters.
+func compileOpenAPIParams(compiler *schemaCompiler, v interface{}) ([]*openAPIParam, error) {
+	list, _ := v.([]interface{})
+	params := make([]*openAPIParam, 0, len(list))
+	for i, item := range list {
+		m, err := deref(compiler.doc, item)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+		p := &openAPIParam{}
+		p.name, _ = m["name"].(string)
+		p.in, _ = m["in"].(string)
+		p.required, _ = m["required"].(bool)
+		if p.name == "" || p.in == "" {
+			return nil, fmt.Errorf("%d: parameter must have a name and a location", i)
+		}
+		if p.in == "header" && ignoredHeaderParam(p.name) {
+			continue
+		}
+		if s, ok := m["schema"]; ok {
+			if p.schema, err = compiler.compile(s); err != nil {
+				return nil, fmt.Errorf("%d/schema: %w", i, err)
+			}
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// ignoredHeaderParam reports whether a header parameter describes a header that OpenAPI declares elsewhere,
+// in the request body content and the security schemes, so that its definition must be ignored.
+func ignoredHeaderParam(name string) bool {
+	for _, header := range []string{fiber.HeaderAccept, fiber.HeaderContentType, fiber.HeaderAuthorization} {
+		if strings.EqualFold(name, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileOpenAPIBody compiles a request body.
+func compileOpenAPIBody(compiler *schemaCompiler, v interface{}) (*openAPIBody, error) {
+	m, err := deref(compiler.doc, v)
+	if err != nil {
+		return nil, err
+	}
+	body := &openAPIBody{content: make(map[string]*schema)}
+	body.required, _ = m["required"].(bool)
+
+	content, _ := m["content"].(map[string]interface{})
+	for mediaType, media := range content {
+		mm, _ := media.(map[string]interface{})
+		var s *schema
+		if raw, ok := mm["schema"]; ok {
+			if s, err = compiler.compile(raw); err != nil {
+				return nil, fmt.Errorf("content/%s/schema: %w", escapePointer(mediaType), err)
+			}
+		}
+		body.content[strings.ToLower(mediaType)] = s
+	}
+	return body, nil
+}
+
+// Restrict implements the Restrictor interface for RestrictOpenAPI.
+func (r *RestrictOpenAPI) Restrict(c *fiber.Ctx) error {
+	path := c.Path()
+	if r.BasePath != "" {
+		if !strings.HasPrefix(path, r.BasePath) {
+			return r.unknownOperation(c.Method(), path, false)
+		}
+		path = "/" + strings.TrimLeft(strings.TrimPrefix(path, r.BasePath), "/")
+	}
+
+	pathFound := false
+	for _, p := range r.paths {
+		match := p.re.FindStringSubmatch(path)
+		if match == nil {
+			continue
+		}
+		pathFound = true
+		operation, ok := p.operations[c.Method()]
+		if !ok {
+			continue
+		}
+
+		values := make(map[string]string, len(p.names))
+		for i, name := range p.names {
+			value, err := url.PathUnescape(match[i+1])
+			if err != nil {
+				value = match[i+1]
+			}
+			values[name] = value
+		}
+		return operation.validate(c, values)
+	}
+
+	return r.unknownOperation(c.Method(), path, pathFound)
+}
+
+// unknownOperation returns the error for a request that matches no operation, unless unknown operations are allowed.
+func (r *RestrictOpenAPI) unknownOperation(method, path string, pathFound bool) error {
+	switch {
+	case r.AllowUnknownOperations:
+		return nil
+	case pathFound:
+		return errMethodNotAllowed(method, path)
+	default:
+		return errOperationNotFound(method, path)
+	}
+}
+
+// validate validates the parameters and the request body of a request against the operation.
+func (o *openAPIOperation) validate(c *fiber.Ctx, pathValues map[string]string) error {
+	for _, p := range o.params {
+		var values []string
+		switch p.in {
+		case "path":
+			if v, ok := pathValues[p.name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			for _, v := range c.Context().QueryArgs().PeekMulti(p.name) {
+				values = append(values, string(v))
+			}
+		case "header":
+			if v := c.Get(p.name); v != "" {
+				values = []string{v}
+			}
+		case "cookie":
+			if v := c.Cookies(p.name); v != "" {
+				values = []string{v}
+			}
+		}
+
+		pointer := "/" + p.in + "/" + escapePointer(p.name)
+		if len(values) == 0 {
+			if p.required {
+				return errSchemaViolation(pointer, "is required", "required", nil, nil)
+			}
+			continue
+		}
+		if p.schema == nil {
+			continue
+		}
+
+		// Arrays in path and header parameters are comma-separated (style "simple").
+		if (p.in == "path" || p.in == "header") && p.schema.hasType("array") {
+			values = strings.Split(values[0], ",")
+		}
+		if violation := p.schema.validate(p.schema.coerceAll(values), pointer); violation != nil {
+			return violation.err()
+		}
+	}
+
+	if o.body != nil {
+		return o.body.validate(c)
+	}
+	return nil
+}
+
+// validate validates the request body against the schema of its media type.
+func (b *openAPIBody) validate(c *fiber.Ctx) error {
+	if len(c.Body()) == 0 {
+		if b.required {
+			return errSchemaViolation("/body", "is required", "required", nil, nil)
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if err != nil {
+		return errUnsupportedMediaType(c.Get(fiber.HeaderContentType))
+	}
+	s, ok := b.schemaFor(mediaType)
+	if !ok {
+		return errUnsupportedMediaType(mediaType)
+	}
+	if s == nil {
+		return nil
+	}
+
+	var value interface{}
+	switch {
+	case mediaType == fiber.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json"):
+		if err := json.Unmarshal(c.Body(), &value); err != nil {
+			return errInvalidJSONBody(err)
+		}
+	case mediaType == fiber.MIMEApplicationForm:
+		form := make(map[string][]string)
+		c.Context().PostArgs().VisitAll(func(key, v []byte) {
+			form[string(key)] = append(form[string(key)], string(v))
+		})
+		value = formValue(s, form)
+	case mediaType == fiber.MIMEMultipartForm:
+		mf, err := c.MultipartForm()
+		if err != nil {
+			return errInvalidBody(err)
+		}
+		form := make(map[string][]string, len(mf.Value)+len(mf.File))
+		for key, values := range mf.Value {
+			form[key] = values
+		}
+		// Files are represented by their file names.
+		for key, files := range mf.File {
+			for _, f := range files {
+				form[key] = append(form[key], f.Filename)
+			}
+		}
+		value = formValue(s, form)
+	default:
+		value = string(c.Body())
+	}
+
+	if violation := s.validate(value, "/body"); violation != nil {
+		return violation.err()
+	}
+	return nil
+}
+
+// schemaFor returns the schema of a media type, matching structured syntax suffixes and wildcards of the specification.
+func (b *openAPIBody) schemaFor(mediaType string) (*schema, bool) {
+	candidates := []string{mediaType}
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		if slash := strings.IndexByte(mediaType, '/'); slash >= 0 {
+			candidates = append(candidates, mediaType[:slash+1]+mediaType[i+1:])
+		}
+	}
+	if slash := strings.IndexByte(mediaType, '/'); slash >= 0 {
+		candidates = append(candidates, mediaType[:slash]+"/*")
+	}
+	candidates = append(candidates, "*/*")
+
+	for _, candidate := range candidates {
+		if s, ok := b.content[candidate]; ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// formValue converts form fields to an object, converting the values to the types of their properties.
+func formValue(s *schema, form map[string][]string) map[string]interface{} {
+	value := make(map[string]interface{}, len(form))
+	for key, values := range form {
+		value[key] = s.property(key).coerceAll(values)
+	}
+	return value
+}
+
+// deref returns the object a value refers to if it is a reference, or the value itself.
+func deref(doc, v interface{}) (map[string]interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("must be an object")
+	}
+	for i := 0; i < 32; i++ {
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m, nil
+		}
+		target, err := resolveRef(doc, ref)
+		if err != nil {
+			return nil, err
+		}
+		if m, ok = target.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("reference %q must refer to an object", ref)
+		}
+	}
+	return nil, errors.New("too many nested references")
+}
+
+// checkLocalRefs returns an error if the document contains a reference that is not local.
+func checkLocalRefs(v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if ref, ok := child.(string); ok && k == "$ref" && !strings.HasPrefix(ref, "#") {
+				return fmt.Errorf("remote reference %q is not supported", ref)
+			}
+			if err := checkLocalRefs(child); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := checkLocalRefs(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
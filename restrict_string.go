@@ -6,9 +6,7 @@ package validator
 
 import (
 	"encoding/xml"
-	"fmt"
 	"reflect"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/text/cases"
@@ -23,35 +21,82 @@ type RestrictStringLength struct {
 
 	// MaxLength specifies the maximum allowed length for the fields (optional).
 	MaxLength *int
+
+	// MaxBodySize specifies the maximum allowed size of the request body in bytes for this rule (optional).
+	// Larger requests are rejected with a 413 status before the body is parsed.
+	MaxBodySize int
+
+	// StrictTypes rejects values of the fields that are not strings, other than null, with a TYPE_MISMATCH error (optional).
+	// By default, values of other types are not checked.
+	StrictTypes bool
+
+	// AllowedContentTypes lists the media types, or media ranges such as "application/*", of the request bodies
+	// accepted by this rule (optional). Request bodies of other media types are rejected with a 415 status,
+	// and accepted request bodies of content types that are not decoded are rejected unless LegacyTextScan is set.
+	AllowedContentTypes []string
+
+	// LegacyTextScan keeps scanning request bodies of content types other than JSON, XML, and the other decoded formats
+	// for the fields as text when AllowedContentTypes or Config.AllowedContentTypes is set (optional).
+	// See Config.LegacyTextScan.
+	LegacyTextScan bool
+
+	// ReportOnly reports violations of this rule without rejecting the request (optional).
+	// See Config.ReportOnly for how violations are reported.
+	ReportOnly bool
+
+	// Message overrides the error message of this rule (optional).
+	// See ErrorOverride for the available placeholders.
+	Message string
+
+	// Status overrides the HTTP status code of this rule's errors (optional).
+	Status int
+
+	// FieldOverrides overrides the error message or status code for individual fields (optional).
+	FieldOverrides map[string]ErrorOverride
 }
 
 // Restrict implements the Restrictor interface for RestrictStringLength.
 // It checks the specified fields in the request body for string length based on the content type.
 func (r RestrictStringLength) Restrict(c *fiber.Ctx) error {
-	return restrictByContentType(c, r.restrictJSON, r.restrictXML, r.restrictOther)
+	if err := restrictBodySize(c, r.MaxBodySize); err != nil {
+		return err
+	}
+	if err := restrictContentType(c, r.AllowedContentTypes); err != nil {
+		return err
+	}
+	err := restrictByContentType(c, r.LegacyTextScan, r.AllowedContentTypes, r.restrictDocument, r.restrictXML, r.restrictOther)
+	return overrideError(err, ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
 }
 
-// restrictJSON checks the specified fields in the JSON request body for string length and maximum limit.
-func (r RestrictStringLength) restrictJSON(c *fiber.Ctx) error {
-	var body map[string]interface{}
-	if err := c.BodyParser(&body); err != nil {
-		return NewError(fiber.StatusBadRequest, ErrInvalidJSONBody)
-	}
+// isReportOnly implements the reportOnlyRestrictor interface for RestrictStringLength.
+func (r RestrictStringLength) isReportOnly() bool {
+	return r.ReportOnly
+}
+
+// restrictElement implements the elementRestrictor interface for RestrictStringLength.
+func (r RestrictStringLength) restrictElement(c *fiber.Ctx, body map[string]interface{}) error {
+	return overrideError(r.restrictDocument(c, body), ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
 
+// restrictDocument checks the specified fields in the decoded request body for string length and maximum limit.
+func (r RestrictStringLength) restrictDocument(c *fiber.Ctx, body map[string]interface{}) error {
 	var invalidFields []string
 	for _, field := range r.Fields {
 		value, ok := body[field]
 		if ok {
 			if str, ok := value.(string); ok {
 				if r.MaxLength != nil && len(str) > *r.MaxLength {
-					return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumLength, field, *r.MaxLength))
+					return errMaxLengthExceeded(field, str, *r.MaxLength)
 				}
+			} else if r.StrictTypes && value != nil {
+				return errTypeMismatch(field, TypeString, true, value)
 			}
+			recordValue(c, field, value)
 		}
 	}
 
 	if len(invalidFields) > 0 {
-		return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldsExceedMaximumLength, strings.Join(invalidFields, "', '")))
+		return errFieldsExceedMaximumLength(invalidFields)
 	}
 
 	return nil
@@ -72,19 +117,20 @@ func (r RestrictStringLength) restrictXML(c *fiber.Ctx) error {
 	bodyValue := reflect.New(bodyType).Elem()
 
 	if err := xml.Unmarshal(c.Body(), bodyValue.Addr().Interface()); err != nil {
-		return NewError(fiber.StatusBadRequest, ErrInvalidXMLBody)
+		return errInvalidXMLBody(err)
 	}
 
 	var invalidFields []string
 	for _, field := range r.Fields {
 		value := bodyValue.FieldByName(caser.String(field)).String()
 		if r.MaxLength != nil && len(value) > *r.MaxLength {
-			return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumLength, field, *r.MaxLength))
+			return errMaxLengthExceeded(field, value, *r.MaxLength)
 		}
+		recordValue(c, field, value)
 	}
 
 	if len(invalidFields) > 0 {
-		return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldsExceedMaximumLength, strings.Join(invalidFields, "', '")))
+		return errFieldsExceedMaximumLength(invalidFields)
 	}
 
 	return nil
@@ -98,12 +144,13 @@ func (r RestrictStringLength) restrictOther(c *fiber.Ctx) error {
 	for _, field := range r.Fields {
 		fieldValue := extractFieldValue(body, field, RestrictUnicode{Fields: r.Fields})
 		if r.MaxLength != nil && len(fieldValue) > *r.MaxLength {
-			return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldExceedsMaximumLength, field, *r.MaxLength))
+			return errMaxLengthExceeded(field, fieldValue, *r.MaxLength)
 		}
+		recordValue(c, field, fieldValue)
 	}
 
 	if len(invalidFields) > 0 {
-		return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrFieldsExceedMaximumLength, strings.Join(invalidFields, "', '")))
+		return errFieldsExceedMaximumLength(invalidFields)
 	}
 
 	return nil
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JSONType is the type of a value in the JSON document model.
+type JSONType string
+
+// JSON types that RestrictType can require.
+const (
+	TypeString  JSONType = "string"
+	TypeNumber  JSONType = "number"
+	TypeInteger JSONType = "integer"
+	TypeBoolean JSONType = "boolean"
+	TypeArray   JSONType = "array"
+	TypeObject  JSONType = "object"
+)
+
+// RestrictType is a Restrictor implementation that requires the specified fields of the request body to be of a JSON type,
+// so that values of another type cannot bypass rules that only check values of their own type.
+// It applies to JSON request bodies and to the other formats that are decoded into the same document model,
+// such as MessagePack, CBOR, YAML, and TOML. Fields missing from the request body are not checked.
+//
+// XML request bodies and request bodies scanned as text only contain strings, so their fields are not checked.
+type RestrictType struct {
+	// Fields specifies the fields to check.
+	Fields []string
+
+	// Type specifies the required type of the fields.
+	// TypeInteger accepts numbers without a fractional part.
+	Type JSONType
+
+	// Nullable accepts null in addition to the required type (optional).
+	Nullable bool
+
+	// MaxBodySize specifies the maximum allowed size of the request body in bytes for this rule (optional).
+	// Larger requests are rejected with a 413 status before the body is parsed.
+	MaxBodySize int
+
+	// ReportOnly reports violations of this rule without rejecting the request (optional).
+	// See Config.ReportOnly for how violations are reported.
+	ReportOnly bool
+
+	// Message overrides the error message of this rule (optional).
+	// See ErrorOverride for the available placeholders.
+	Message string
+
+	// Status overrides the HTTP status code of this rule's errors (optional).
+	Status int
+
+	// FieldOverrides overrides the error message or status code for individual fields (optional).
+	FieldOverrides map[string]ErrorOverride
+}
+
+// Restrict implements the Restrictor interface for RestrictType.
+// It checks the types of the specified fields in the decoded request body.
+func (r RestrictType) Restrict(c *fiber.Ctx) error {
+	if err := restrictBodySize(c, r.MaxBodySize); err != nil {
+		return err
+	}
+	err := restrictByContentType(c, false, nil, r.restrictDocument, r.restrictText, r.restrictText)
+	return overrideError(err, ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
+
+// isReportOnly implements the reportOnlyRestrictor interface for RestrictType.
+func (r RestrictType) isReportOnly() bool {
+	return r.ReportOnly
+}
+
+// restrictElement implements the elementRestrictor interface for RestrictType.
+func (r RestrictType) restrictElement(c *fiber.Ctx, body map[string]interface{}) error {
+	return overrideError(r.restrictDocument(c, body), ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
+
+// restrictDocument checks the types of the specified fields in the decoded request body.
+func (r RestrictType) restrictDocument(c *fiber.Ctx, body map[string]interface{}) error {
+	for _, field := range r.Fields {
+		value, ok := body[field]
+		if !ok {
+			continue
+		}
+		if !(value == nil && r.Nullable) && !isJSONType(value, r.Type) {
+			return errTypeMismatch(field, r.Type, r.Nullable, value)
+		}
+		recordValue(c, field, value)
+	}
+	return nil
+}
+
+// restrictText accepts XML request bodies and request bodies scanned as text, whose values are all strings.
+func (r RestrictType) restrictText(c *fiber.Ctx) error {
+	return nil
+}
+
+// describeSchema implements the schemaRule interface for RestrictType.
+func (r RestrictType) describeSchema(b *schemaBuilder) {
+	b.constrain(r.Fields, "type", string(r.Type))
+	if r.Nullable {
+		b.constrain(r.Fields, "nullable", true)
+	}
+}
+
+// isJSONType reports whether a value of the JSON document model is of the JSON type.
+func isJSONType(value interface{}, typ JSONType) bool {
+	if typ == TypeInteger {
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	}
+	return jsonTypeOf(value) == typ
+}
+
+// jsonTypeOf returns the JSON type of a value of the JSON document model, or "null" for nil.
+func jsonTypeOf(value interface{}) JSONType {
+	switch value.(type) {
+	case string:
+		return TypeString
+	case float64:
+		return TypeNumber
+	case bool:
+		return TypeBoolean
+	case []interface{}:
+		return TypeArray
+	case map[string]interface{}:
+		return TypeObject
+	default:
+		return "null"
+	}
+}
+
+// validJSONType reports whether typ is one of the JSON types that RestrictType can require.
+func validJSONType(typ JSONType) bool {
+	switch typ {
+	case TypeString, TypeNumber, TypeInteger, TypeBoolean, TypeArray, TypeObject:
+		return true
+	}
+	return false
+}
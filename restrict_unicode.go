@@ -6,7 +6,6 @@ package validator
 
 import (
 	"encoding/xml"
-	"fmt"
 	"reflect"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,28 +18,76 @@ import (
 type RestrictUnicode struct {
 	// Fields specifies the fields to check for Unicode characters.
 	Fields []string
+
+	// MaxBodySize specifies the maximum allowed size of the request body in bytes for this rule (optional).
+	// Larger requests are rejected with a 413 status before the body is parsed.
+	MaxBodySize int
+
+	// StrictTypes rejects values of the fields that are not strings, other than null, with a TYPE_MISMATCH error (optional).
+	// By default, values of other types are not checked.
+	StrictTypes bool
+
+	// AllowedContentTypes lists the media types, or media ranges such as "application/*", of the request bodies
+	// accepted by this rule (optional). Request bodies of other media types are rejected with a 415 status,
+	// and accepted request bodies of content types that are not decoded are rejected unless LegacyTextScan is set.
+	AllowedContentTypes []string
+
+	// LegacyTextScan keeps scanning request bodies of content types other than JSON, XML, and the other decoded formats
+	// for the fields as text when AllowedContentTypes or Config.AllowedContentTypes is set (optional).
+	// See Config.LegacyTextScan.
+	LegacyTextScan bool
+
+	// ReportOnly reports violations of this rule without rejecting the request (optional).
+	// See Config.ReportOnly for how violations are reported.
+	ReportOnly bool
+
+	// Message overrides the error message of this rule (optional).
+	// See ErrorOverride for the available placeholders.
+	Message string
+
+	// Status overrides the HTTP status code of this rule's errors (optional).
+	Status int
+
+	// FieldOverrides overrides the error message or status code for individual fields (optional).
+	FieldOverrides map[string]ErrorOverride
 }
 
 // Restrict implements the Restrictor interface for RestrictUnicode.
 // It checks the specified fields in the request body for Unicode characters based on the content type.
 func (r RestrictUnicode) Restrict(c *fiber.Ctx) error {
-	return restrictByContentType(c, r.restrictJSON, r.restrictXML, r.restrictOther)
+	if err := restrictBodySize(c, r.MaxBodySize); err != nil {
+		return err
+	}
+	if err := restrictContentType(c, r.AllowedContentTypes); err != nil {
+		return err
+	}
+	err := restrictByContentType(c, r.LegacyTextScan, r.AllowedContentTypes, r.restrictDocument, r.restrictXML, r.restrictOther)
+	return overrideError(err, ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
 }
 
-// restrictJSON checks the specified fields in the JSON request body for Unicode characters.
-func (r RestrictUnicode) restrictJSON(c *fiber.Ctx) error {
-	var body map[string]interface{}
-	if err := c.BodyParser(&body); err != nil {
-		return NewError(fiber.StatusBadRequest, ErrInvalidJSONBody)
-	}
+// isReportOnly implements the reportOnlyRestrictor interface for RestrictUnicode.
+func (r RestrictUnicode) isReportOnly() bool {
+	return r.ReportOnly
+}
+
+// restrictElement implements the elementRestrictor interface for RestrictUnicode.
+func (r RestrictUnicode) restrictElement(c *fiber.Ctx, body map[string]interface{}) error {
+	return overrideError(r.restrictDocument(c, body), ErrorOverride{Message: r.Message, Status: r.Status}, r.FieldOverrides)
+}
+
+// restrictDocument checks the specified fields in the decoded request body for Unicode characters.
+func (r RestrictUnicode) restrictDocument(c *fiber.Ctx, body map[string]interface{}) error {
 	for _, field := range r.Fields {
 		value, ok := body[field]
 		if ok {
 			if str, ok := value.(string); ok {
 				if containsUnicode(str) {
-					return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrUnicodeNotAllowedInField, field))
+					return errUnicodeNotAllowed(field, str)
 				}
+			} else if r.StrictTypes && value != nil {
+				return errTypeMismatch(field, TypeString, true, value)
 			}
+			recordValue(c, field, value)
 		}
 	}
 	return nil
@@ -65,14 +112,15 @@ func (r RestrictUnicode) restrictXML(c *fiber.Ctx) error {
 	bodyValue := reflect.New(bodyType).Elem()
 
 	if err := xml.Unmarshal(c.Body(), bodyValue.Addr().Interface()); err != nil {
-		return NewError(fiber.StatusBadRequest, ErrInvalidXMLBody)
+		return errInvalidXMLBody(err)
 	}
 
 	for _, field := range r.Fields {
 		value := bodyValue.FieldByName(caser.String(field)).String()
 		if containsUnicode(value) {
-			return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrUnicodeNotAllowedInField, field))
+			return errUnicodeNotAllowed(field, value)
 		}
+		recordValue(c, field, value)
 	}
 	return nil
 }
@@ -83,8 +131,9 @@ func (r RestrictUnicode) restrictOther(c *fiber.Ctx) error {
 	for _, field := range r.Fields {
 		fieldValue := extractFieldValue(body, field, r)
 		if containsUnicode(fieldValue) {
-			return NewError(fiber.StatusBadRequest, fmt.Sprintf(ErrUnicodeNotAllowedInField, field))
+			return errUnicodeNotAllowed(field, fieldValue)
 		}
+		recordValue(c, field, fieldValue)
 	}
 	return nil
 }
@@ -0,0 +1,187 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// resultKey is the context key under which the Result of a request is stored.
+type resultKey struct{}
+
+// Result is the outcome of validating a request. It is stored in the context for ResultFrom,
+// and under Config.ContextKey when set.
+type Result struct {
+	// Passed is true when no rule rejected the request. Reported violations do not fail the request.
+	Passed bool
+
+	// Err is the violation that rejected the request, or nil when the request passed.
+	Err error
+
+	// Reported are the violations that were reported instead of enforced.
+	Reported []error
+
+	// Rules are the evaluations of the rules that ran, in order.
+	// Rules after the one that rejected the request are not evaluated.
+	Rules []Evaluation
+
+	// Fields are the outcomes of the individual fields checked by the rules, in order.
+	// Fields missing from the request body are not checked and therefore not listed.
+	Fields []FieldResult
+
+	// Values are the sanitized values of the checked fields that passed every rule that checked them.
+	// Strings longer than 64 characters are truncated, and the values of fields whose names suggest
+	// a secret, such as "password" or "api_key", are replaced with "[REDACTED]".
+	// They are only recorded when Config.RecordValues is set.
+	Values map[string]interface{}
+
+	// Duration is the total time spent evaluating the rules.
+	Duration time.Duration
+
+	// checked are the fields checked by the rule that is currently being evaluated.
+	checked []string
+
+	// recordValues records the values of the checked fields in Values.
+	recordValues bool
+}
+
+// FieldResult is the outcome of a single field checked by a rule.
+type FieldResult struct {
+	// Field is the name of the field.
+	Field string
+
+	// Rule is the name of the rule that checked the field.
+	Rule string
+
+	// Err is the violation of the field, or nil when the field passed.
+	Err error
+}
+
+// Passed reports whether the field passed the rule.
+func (f FieldResult) Passed() bool {
+	return f.Err == nil
+}
+
+// ResultFrom returns the Result of validating the request, or nil if the validator did not run for the request,
+// for example because Config.Next skipped it.
+//
+// Example:
+//
+//	app.Post("/login", func(c *fiber.Ctx) error {
+//		result := validator.ResultFrom(c)
+//		if result == nil || !result.Passed {
+//			return fiber.ErrUnauthorized
+//		}
+//		// ...
+//	})
+func ResultFrom(c *fiber.Ctx) *Result {
+	result, _ := c.Locals(resultKey{}).(*Result)
+	return result
+}
+
+// recordValue records a field that passed the rule currently being evaluated, and its value if Config.RecordValues is set.
+func recordValue(c *fiber.Ctx, field string, value interface{}) {
+	result := ResultFrom(c)
+	if result == nil {
+		return
+	}
+	if result.recordValues {
+		if result.Values == nil {
+			result.Values = make(map[string]interface{})
+		}
+		result.Values[field] = sanitizeValue(field, value)
+	}
+	result.checked = append(result.checked, field)
+}
+
+// maxRecordedValueLength is the number of characters of a string that is recorded in Result.Values.
+const maxRecordedValueLength = 64
+
+// redactedValue is recorded in Result.Values instead of the values of fields that appear to hold secrets.
+const redactedValue = "[REDACTED]"
+
+// sensitiveFieldNames are the parts of field names that suggest a secret. Field names are compared
+// in lower case and without "_" and "-", so that "api_key" and "API-Key" both contain "apikey".
+var sensitiveFieldNames = []string{
+	"password", "passwd", "secret", "token", "apikey", "authorization", "credential", "cookie", "session",
+}
+
+// sanitizeValue returns the value of a field as recorded in Result.Values, masking the values of fields
+// that appear to hold secrets and truncating long strings, also inside arrays and objects.
+func sanitizeValue(field string, value interface{}) interface{} {
+	name := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(field))
+	for _, sensitive := range sensitiveFieldNames {
+		if strings.Contains(name, sensitive) {
+			return redactedValue
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if utf8.RuneCountInString(v) > maxRecordedValueLength {
+			return string([]rune(v)[:maxRecordedValueLength]) + "…"
+		}
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = sanitizeValue(field, item)
+		}
+		return items
+	case map[string]interface{}:
+		members := make(map[string]interface{}, len(v))
+		for key, member := range v {
+			members[key] = sanitizeValue(key, member)
+		}
+		return members
+	}
+	return value
+}
+
+// addEvaluation records the evaluation of a rule and the outcomes of the fields it checked.
+func (r *Result) addEvaluation(e Evaluation) {
+	r.Rules = append(r.Rules, e)
+
+	for _, field := range r.checked {
+		r.Fields = append(r.Fields, FieldResult{Field: field, Rule: e.Rule})
+	}
+	r.checked = r.checked[:0]
+
+	for _, field := range errorFields(e.Err) {
+		r.Fields = append(r.Fields, FieldResult{Field: field, Rule: e.Rule, Err: e.Err})
+	}
+}
+
+// finish completes the result once validation has ended, removing the values of fields that failed any rule.
+func (r *Result) finish(err error, reported []error, duration time.Duration) {
+	r.Passed = err == nil
+	r.Err = err
+	r.Reported = reported
+	r.Duration = duration
+	r.checked = nil
+
+	for _, f := range r.Fields {
+		if f.Err != nil {
+			delete(r.Values, f.Field)
+		}
+	}
+}
+
+// errorFields returns the fields that a violation concerns.
+func errorFields(err error) []string {
+	var ve *Error
+	if !errors.As(err, &ve) {
+		return nil
+	}
+	if ve.Field != "" {
+		return []string{ve.Field}
+	}
+	fields, _ := ve.Params["fields"].([]string)
+	return fields
+}
@@ -0,0 +1,630 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// schema is a compiled JSON Schema, covering the keywords used to describe request data in OpenAPI 3.0 and 3.1.
+type schema struct {
+	never bool
+
+	types    []string
+	nullable bool
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+
+	properties    map[string]*schema
+	required      []string
+	additional    *schema
+	minProperties *int
+	maxProperties *int
+
+	items       *schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	allOf []*schema
+	anyOf []*schema
+	oneOf []*schema
+	not   *schema
+}
+
+// schemaViolation describes the first violation of a schema found in a value.
+type schemaViolation struct {
+	pointer string
+	reason  string
+	keyword string
+	limit   interface{}
+	value   interface{}
+}
+
+// err returns the violation as an *Error.
+func (v *schemaViolation) err() *Error {
+	return errSchemaViolation(v.pointer, v.reason, v.keyword, v.limit, v.value)
+}
+
+// schemaCompiler compiles the schemas of a document, resolving local references.
+type schemaCompiler struct {
+	doc  interface{}
+	refs map[string]*schema
+}
+
+// compile compiles a schema. Referenced schemas are compiled once, which also allows recursive schemas.
+func (c *schemaCompiler) compile(v interface{}) (*schema, error) {
+	switch v := v.(type) {
+	case bool:
+		// OpenAPI 3.1 allows the boolean schemas true and false.
+		return &schema{never: !v}, nil
+	case map[string]interface{}:
+		s := &schema{}
+		return s, c.compileInto(s, v)
+	default:
+		return nil, fmt.Errorf("schema must be an object, got %T", v)
+	}
+}
+
+// ref returns the compiled schema of a local reference.
+func (c *schemaCompiler) ref(ref string) (*schema, error) {
+	if s, ok := c.refs[ref]; ok {
+		return s, nil
+	}
+	target, err := resolveRef(c.doc, ref)
+	if err != nil {
+		return nil, err
+	}
+	s := &schema{}
+	c.refs[ref] = s
+	switch target := target.(type) {
+	case bool:
+		s.never = !target
+	case map[string]interface{}:
+		if err := c.compileInto(s, target); err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: schema must be an object", ref)
+	}
+	return s, nil
+}
+
+// compileInto compiles the keywords of a schema object into s.
+func (c *schemaCompiler) compileInto(s *schema, m map[string]interface{}) error {
+	var err error
+
+	// A reference is applied like an allOf, which covers both the OpenAPI 3.0 and 3.1 semantics.
+	if ref, ok := m["$ref"].(string); ok {
+		target, err := c.ref(ref)
+		if err != nil {
+			return err
+		}
+		s.allOf = append(s.allOf, target)
+	}
+
+	switch t := m["type"].(type) {
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			name, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("type must be a string or an array of strings")
+			}
+			s.types = append(s.types, name)
+		}
+	case nil:
+	default:
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	s.nullable, _ = m["nullable"].(bool)
+
+	if enum, ok := m["enum"].([]interface{}); ok {
+		for _, v := range enum {
+			s.enum = append(s.enum, normalizeNumbers(v))
+		}
+	}
+	if v, ok := m["const"]; ok {
+		s.constant, s.hasConst = normalizeNumbers(v), true
+	}
+
+	if props, ok := m["properties"].(map[string]interface{}); ok {
+		s.properties = make(map[string]*schema, len(props))
+		for name, v := range props {
+			if s.properties[name], err = c.compile(v); err != nil {
+				return fmt.Errorf("properties/%s: %w", name, err)
+			}
+		}
+	}
+	if required, ok := m["required"].([]interface{}); ok {
+		for _, v := range required {
+			if name, ok := v.(string); ok {
+				s.required = append(s.required, name)
+			}
+		}
+	}
+	if v, ok := m["additionalProperties"]; ok {
+		if s.additional, err = c.compile(v); err != nil {
+			return fmt.Errorf("additionalProperties: %w", err)
+		}
+	}
+	s.minProperties = schemaInt(m, "minProperties")
+	s.maxProperties = schemaInt(m, "maxProperties")
+
+	if v, ok := m["items"]; ok {
+		if s.items, err = c.compile(v); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+	}
+	s.minItems = schemaInt(m, "minItems")
+	s.maxItems = schemaInt(m, "maxItems")
+	s.uniqueItems, _ = m["uniqueItems"].(bool)
+
+	s.minLength = schemaInt(m, "minLength")
+	s.maxLength = schemaInt(m, "maxLength")
+	if pattern, ok := m["pattern"].(string); ok {
+		if s.pattern, err = regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("pattern: %w", err)
+		}
+	}
+
+	s.minimum = schemaFloat(m, "minimum")
+	s.maximum = schemaFloat(m, "maximum")
+	s.multipleOf = schemaFloat(m, "multipleOf")
+	// OpenAPI 3.0 uses booleans that modify minimum and maximum, OpenAPI 3.1 uses numbers.
+	if exclusive, ok := m["exclusiveMinimum"].(bool); ok {
+		if exclusive {
+			s.exclusiveMinimum, s.minimum = s.minimum, nil
+		}
+	} else {
+		s.exclusiveMinimum = schemaFloat(m, "exclusiveMinimum")
+	}
+	if exclusive, ok := m["exclusiveMaximum"].(bool); ok {
+		if exclusive {
+			s.exclusiveMaximum, s.maximum = s.maximum, nil
+		}
+	} else {
+		s.exclusiveMaximum = schemaFloat(m, "exclusiveMaximum")
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		list, ok := m[keyword].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, v := range list {
+			sub, err := c.compile(v)
+			if err != nil {
+				return fmt.Errorf("%s/%d: %w", keyword, i, err)
+			}
+			switch keyword {
+			case "allOf":
+				s.allOf = append(s.allOf, sub)
+			case "anyOf":
+				s.anyOf = append(s.anyOf, sub)
+			case "oneOf":
+				s.oneOf = append(s.oneOf, sub)
+			}
+		}
+	}
+	if v, ok := m["not"]; ok {
+		if s.not, err = c.compile(v); err != nil {
+			return fmt.Errorf("not: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validate returns the first violation of the schema by v, which is located at pointer, or nil if v is valid.
+// Values are expected in the form produced by encoding/json, with numbers as float64.
+func (s *schema) validate(v interface{}, pointer string) *schemaViolation {
+	violation := func(keyword, reason string, limit interface{}) *schemaViolation {
+		return &schemaViolation{pointer: pointer, reason: reason, keyword: keyword, limit: limit, value: v}
+	}
+
+	if s.never {
+		return violation("false", "is not allowed", nil)
+	}
+	if v == nil && s.nullable {
+		return nil
+	}
+	if len(s.types) > 0 && !matchesType(v, s.types) {
+		return violation("type", "must be of type "+strings.Join(s.types, " or "), nil)
+	}
+	if s.enum != nil && !containsValue(s.enum, v) {
+		return violation("enum", "must be one of the allowed values", nil)
+	}
+	if s.hasConst && !reflect.DeepEqual(s.constant, v) {
+		return violation("const", "must be equal to the allowed value", nil)
+	}
+
+	switch v := v.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.minLength != nil && length < *s.minLength {
+			return violation("minLength", fmt.Sprintf("must be at least %d characters", *s.minLength), *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			return violation("maxLength", fmt.Sprintf("must not exceed %d characters", *s.maxLength), *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			return violation("pattern", fmt.Sprintf("must match the pattern '%s'", s.pattern), s.pattern.String())
+		}
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			return violation("minimum", "must be at least "+formatNumber(*s.minimum), *s.minimum)
+		}
+		if s.maximum != nil && v > *s.maximum {
+			return violation("maximum", "must not exceed "+formatNumber(*s.maximum), *s.maximum)
+		}
+		if s.exclusiveMinimum != nil && v <= *s.exclusiveMinimum {
+			return violation("exclusiveMinimum", "must be greater than "+formatNumber(*s.exclusiveMinimum), *s.exclusiveMinimum)
+		}
+		if s.exclusiveMaximum != nil && v >= *s.exclusiveMaximum {
+			return violation("exclusiveMaximum", "must be less than "+formatNumber(*s.exclusiveMaximum), *s.exclusiveMaximum)
+		}
+		if s.multipleOf != nil && *s.multipleOf > 0 {
+			if q := v / *s.multipleOf; q != math.Trunc(q) {
+				return violation("multipleOf", "must be a multiple of "+formatNumber(*s.multipleOf), *s.multipleOf)
+			}
+		}
+	case []interface{}:
+		if s.minItems != nil && len(v) < *s.minItems {
+			return violation("minItems", fmt.Sprintf("must contain at least %d items", *s.minItems), *s.minItems)
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			return violation("maxItems", fmt.Sprintf("must not contain more than %d items", *s.maxItems), *s.maxItems)
+		}
+		if s.uniqueItems && hasDuplicates(v) {
+			return violation("uniqueItems", "must not contain duplicate items", nil)
+		}
+		if s.items != nil {
+			for i, item := range v {
+				if sv := s.items.validate(item, pointer+"/"+strconv.Itoa(i)); sv != nil {
+					return sv
+				}
+			}
+		}
+	case map[string]interface{}:
+		if s.minProperties != nil && len(v) < *s.minProperties {
+			return violation("minProperties", fmt.Sprintf("must contain at least %d properties", *s.minProperties), *s.minProperties)
+		}
+		if s.maxProperties != nil && len(v) > *s.maxProperties {
+			return violation("maxProperties", fmt.Sprintf("must not contain more than %d properties", *s.maxProperties), *s.maxProperties)
+		}
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				return &schemaViolation{pointer: pointer + "/" + escapePointer(name), reason: "is required", keyword: "required"}
+			}
+		}
+		// Properties are checked in a stable order, so that the same request always reports the same violation.
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			sub, ok := s.properties[name]
+			if !ok {
+				sub = s.additional
+			}
+			if sub == nil {
+				continue
+			}
+			if sub.never && !ok {
+				return &schemaViolation{pointer: pointer + "/" + escapePointer(name), reason: "is not allowed", keyword: "additionalProperties", value: v[name]}
+			}
+			if sv := sub.validate(v[name], pointer+"/"+escapePointer(name)); sv != nil {
+				return sv
+			}
+		}
+	}
+
+	for _, sub := range s.allOf {
+		if sv := sub.validate(v, pointer); sv != nil {
+			return sv
+		}
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.validate(v, pointer) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return violation("anyOf", "must match at least one of the allowed schemas", nil)
+		}
+	}
+	if len(s.oneOf) > 0 {
+		matched := 0
+		for _, sub := range s.oneOf {
+			if sub.validate(v, pointer) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return violation("oneOf", "must match exactly one of the allowed schemas", nil)
+		}
+	}
+	if s.not != nil && s.not.validate(v, pointer) == nil {
+		return violation("not", "must not match the disallowed schema", nil)
+	}
+
+	return nil
+}
+
+// typeSet returns the types declared by the schema, following allOf and references.
+func (s *schema) typeSet() []string {
+	if len(s.types) > 0 {
+		return s.types
+	}
+	for _, sub := range s.allOf {
+		if types := sub.typeSet(); len(types) > 0 {
+			return types
+		}
+	}
+	return nil
+}
+
+// property returns the schema of a property, following allOf and references.
+func (s *schema) property(name string) *schema {
+	if sub, ok := s.properties[name]; ok {
+		return sub
+	}
+	for _, sub := range s.allOf {
+		if p := sub.property(name); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// itemSchema returns the schema of the items of an array, following allOf and references.
+func (s *schema) itemSchema() *schema {
+	if s.items != nil {
+		return s.items
+	}
+	for _, sub := range s.allOf {
+		if items := sub.itemSchema(); items != nil {
+			return items
+		}
+	}
+	return nil
+}
+
+// hasType reports whether the schema declares the given type.
+func (s *schema) hasType(name string) bool {
+	for _, t := range s.typeSet() {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// coerce converts a string from a parameter or form field to the value its schema expects.
+// Strings that cannot be converted are returned unchanged, so that the type check reports them.
+func (s *schema) coerce(str string) interface{} {
+	if s == nil {
+		return str
+	}
+	if s.hasType("integer") || s.hasType("number") {
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			return f
+		}
+	}
+	if s.hasType("boolean") && (str == "true" || str == "false") {
+		return str == "true"
+	}
+	return str
+}
+
+// coerceAll converts the values of a parameter or form field to the value its schema expects,
+// collecting them into an array if the schema is an array.
+func (s *schema) coerceAll(values []string) interface{} {
+	if s == nil || !s.hasType("array") {
+		if len(values) == 0 {
+			return nil
+		}
+		return s.coerce(values[0])
+	}
+	items := s.itemSchema()
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = items.coerce(v)
+	}
+	return list
+}
+
+// matchesType reports whether v is of one of the JSON Schema types.
+func matchesType(v interface{}, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := v.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := v.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsValue reports whether v is one of the values.
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDuplicates reports whether an array contains the same value more than once.
+func hasDuplicates(values []interface{}) bool {
+	return duplicateIndex(values) >= 0
+}
+
+// duplicateIndex returns the index of the first item of an array that equals an earlier item, or -1 if there is none.
+func duplicateIndex(values []interface{}) int {
+	seen := make(map[string]struct{}, len(values))
+	for i, v := range values {
+		// encoding/json sorts the keys of maps, so equal values have the same encoding.
+		key, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[string(key)]; ok {
+			return i
+		}
+		seen[string(key)] = struct{}{}
+	}
+	return -1
+}
+
+// normalizeNumbers converts the numbers of a decoded YAML or JSON value to float64, as produced by encoding/json.
+func normalizeNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeNumbers(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = normalizeNumbers(v[k])
+		}
+		return v
+	}
+	if f, ok := toFloat(v); ok {
+		return f
+	}
+	return v
+}
+
+// toFloat converts a decoded number to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// schemaInt returns a non-negative integer keyword of a schema object.
+func schemaInt(m map[string]interface{}, keyword string) *int {
+	f, ok := toFloat(m[keyword])
+	if !ok || f < 0 {
+		return nil
+	}
+	n := int(f)
+	return &n
+}
+
+// schemaFloat returns a number keyword of a schema object.
+func schemaFloat(m map[string]interface{}, keyword string) *float64 {
+	f, ok := toFloat(m[keyword])
+	if !ok {
+		return nil
+	}
+	return &f
+}
+
+// formatNumber formats a number without a trailing fraction for whole numbers.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// escapePointer escapes a reference token of a JSON Pointer (RFC 6901).
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// resolveRef resolves a local reference such as "#/components/schemas/User" in a document.
+func resolveRef(doc interface{}, ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("remote reference %q is not supported", ref)
+	}
+	v := doc
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return v, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("reference %q not found", ref)
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("reference %q not found", ref)
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("reference %q not found", ref)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2024 H0llyW00dz All rights reserved.
+//
+// License: BSD 3-Clause License
+
+package validator
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/transform"
+)
+
+// Transcode is a Restrictor implementation that transcodes text request bodies declared in another charset to UTF-8,
+// so that the rules judge their characters and lengths correctly. It is usually set as Config.Transcode,
+// which evaluates it after the body size limit and before any other rule.
+//
+// The charset is taken from the charset parameter of the Content-Type header or, for XML request bodies without one,
+// from their byte order mark, the UTF-16 encoding of their XML declaration, or the encoding of the XML declaration. The request body is replaced with its UTF-8 encoding, and the charset
+// parameter and the XML declaration are updated to match, so that the handler reads the transcoded body as well.
+// Request bodies in a charset that is unknown or not allowed are rejected with a 415 status.
+//
+// Binary request bodies, such as MessagePack and CBOR, and form request bodies, whose values are percent-encoded,
+// are left unchanged.
+type Transcode struct {
+	// AllowedCharsets lists the accepted charsets by their IANA names or aliases, such as "ISO-8859-1" or "Shift_JIS".
+	// UTF-8 is always accepted.
+	//
+	// Optional. Default: nil (every charset known to golang.org/x/text/encoding/ianaindex is accepted)
+	AllowedCharsets []string
+
+	// MaxBodySize specifies the maximum allowed size of the request body in bytes, both before and after it is transcoded,
+	// since a body can grow when it is transcoded. As Config.Transcode, it defaults to Config.MaxBodySize.
+	//
+	// Optional. Default: 0 (no limit)
+	MaxBodySize int
+}
+
+// xmlEncodingPattern matches the encoding of an XML declaration at the start of a request body.
+var xmlEncodingPattern = regexp.MustCompile(`^\s*<\?xml[^>]*?\sencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+// Restrict implements the Restrictor interface for Transcode.
+func (t Transcode) Restrict(c *fiber.Ctx) error {
+	if err := restrictBodySize(c, t.MaxBodySize); err != nil {
+		return err
+	}
+	body := c.Body()
+	if len(body) == 0 {
+		return nil
+	}
+	format := bodyFormatOf(c)
+	if format == formatMsgPack || format == formatCBOR {
+		return nil
+	}
+	mediaType, params, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if err != nil || mediaType == fiber.MIMEApplicationForm || strings.HasPrefix(mediaType, "multipart/") {
+		return nil
+	}
+
+	charset, declared := params["charset"]
+	if !declared && format == formatXML {
+		charset = xmlCharset(body)
+	}
+	if charset == "" {
+		return nil
+	}
+
+	enc, name, ok := lookupCharset(charset)
+	if !ok || !t.allows(name) {
+		return errUnsupportedCharset(charset)
+	}
+	if name == "UTF-8" {
+		return nil
+	}
+
+	// The body is decoded up to one byte over the limit, so that a body that grows too large is not decoded in full.
+	var r io.Reader = transform.NewReader(bytes.NewReader(body), enc.NewDecoder())
+	if t.MaxBodySize > 0 {
+		r = io.LimitReader(r, int64(t.MaxBodySize)+1)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return errInvalidBody(err)
+	}
+	if format == formatXML {
+		decoded = rewriteXMLEncoding(decoded)
+	}
+	if t.MaxBodySize > 0 && len(decoded) > t.MaxBodySize {
+		return errBodyTooLarge(t.MaxBodySize)
+	}
+
+	req := c.Request()
+	req.SetBody(decoded)
+	req.Header.Del(fiber.HeaderContentEncoding)
+	req.Header.SetContentLength(len(decoded))
+	if declared {
+		params["charset"] = "utf-8"
+		req.Header.SetContentType(mime.FormatMediaType(mediaType, params))
+	}
+	return nil
+}
+
+// Name implements the NamedRestrictor interface for Transcode.
+func (t Transcode) Name() string {
+	return "Transcode"
+}
+
+// requestLevel implements the requestRestrictor interface for Transcode.
+func (t Transcode) requestLevel() {}
+
+// alwaysEnforced implements the alwaysEnforcedRestrictor interface for Transcode.
+func (t Transcode) alwaysEnforced() {}
+
+// allows reports whether the charset with the canonical IANA name is accepted.
+func (t Transcode) allows(name string) bool {
+	if len(t.AllowedCharsets) == 0 || name == "UTF-8" {
+		return true
+	}
+	for _, allowed := range t.AllowedCharsets {
+		if _, allowedName, ok := lookupCharset(allowed); ok && allowedName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupCharset returns the encoding of a charset and its canonical IANA name.
+func lookupCharset(charset string) (encoding.Encoding, string, bool) {
+	enc, err := ianaindex.IANA.Encoding(charset)
+	if err != nil || enc == nil {
+		return nil, "", false
+	}
+	name, err := ianaindex.IANA.Name(enc)
+	if err != nil {
+		return nil, "", false
+	}
+	return enc, name, true
+}
+
+// xmlCharset returns the charset of an XML request body without a charset parameter. UTF-16 bodies are recognized
+// by their byte order mark or, without one, by the first characters of their XML declaration, since the encoding
+// of the XML declaration cannot be read before the body is decoded. Other bodies use the encoding of the XML declaration.
+func xmlCharset(body []byte) string {
+	switch {
+	case bytes.HasPrefix(body, []byte{0xFE, 0xFF}), bytes.HasPrefix(body, []byte{0xFF, 0xFE}):
+		return "UTF-16"
+	case bytes.HasPrefix(body, []byte("\x00<\x00?")):
+		return "UTF-16BE"
+	case bytes.HasPrefix(body, []byte("<\x00?\x00")):
+		return "UTF-16LE"
+	}
+	if loc := xmlEncodingPattern.FindSubmatchIndex(body); loc != nil {
+		return string(body[loc[2]:loc[3]])
+	}
+	return ""
+}
+
+// rewriteXMLEncoding declares the encoding of a transcoded XML request body as UTF-8.
+func rewriteXMLEncoding(body []byte) []byte {
+	loc := xmlEncodingPattern.FindSubmatchIndex(body)
+	if loc == nil {
+		return body
+	}
+	out := make([]byte, 0, len(body))
+	out = append(out, body[:loc[2]]...)
+	out = append(out, "UTF-8"...)
+	return append(out, body[loc[3]:]...)
+}
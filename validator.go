@@ -5,11 +5,20 @@
 package validator
 
 import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 // New creates a new Validator middleware with the provided configuration.
 func New(config ...Config) fiber.Handler {
+	return newHandler(configDefault(config...))
+}
+
+// configDefault returns the configuration to use, filling in the defaults of unset options.
+func configDefault(config ...Config) Config {
 	cfg := ConfigDefault
 
 	if len(config) > 0 {
@@ -20,22 +29,132 @@ func New(config ...Config) fiber.Handler {
 		}
 	}
 
+	return cfg
+}
+
+// effectiveRules returns the rules that the middleware evaluates, in order. The final rules run after Config.Rules.
+func effectiveRules(cfg Config, final ...Restrictor) []Restrictor {
+	// The body size, media type, and JSON limits run first so that no rule decodes a body that is ambiguous,
+	// unexpected, or too large, and the body is transcoded before the JSON limits scan it.
+	var rules []Restrictor
+	if cfg.MaxBodySize > 0 {
+		rules = append(rules, maxBodySize(cfg.MaxBodySize))
+	}
+	if len(cfg.AllowedContentTypes) > 0 {
+		rules = append(rules, allowedContentTypes(cfg.AllowedContentTypes))
+	}
+	if cfg.Transcode != nil {
+		transcode := *cfg.Transcode
+		if transcode.MaxBodySize == 0 {
+			transcode.MaxBodySize = cfg.MaxBodySize
+		}
+		rules = append(rules, transcode)
+	}
+	if cfg.JSONLimits != nil {
+		rules = append(rules, *cfg.JSONLimits)
+	}
+	rules = append(rules, cfg.Rules...)
+	return append(rules, final...)
+}
+
+// newHandler creates the Validator middleware. The final rules run after Config.Rules.
+func newHandler(cfg Config, final ...Restrictor) fiber.Handler {
+	source := newRuleSource(cfg, final...)
+
 	return func(c *fiber.Ctx) error {
 		if cfg.Next != nil && cfg.Next(c) {
 			return c.Next()
 		}
 
-		for _, rule := range cfg.Rules {
-			if err := rule.Restrict(c); err != nil {
-				if cfg.ContextKey != "" {
-					c.Locals(cfg.ContextKey, err)
+		result := &Result{recordValues: cfg.RecordValues}
+		c.Locals(resultKey{}, result)
+		if cfg.ContextKey != "" {
+			c.Locals(cfg.ContextKey, result)
+		}
+		if cfg.LegacyTextScan {
+			c.Locals(legacyTextScanKey{}, true)
+		}
+
+		// The rule set is loaded once, so that a reload does not affect a request that is being validated.
+		set := source.load()
+
+		// Compressed request bodies are decoded once for all rules. With a body size limit, the MaxBodySize rule
+		// decodes them within the limit instead. Bodies that cannot be decoded are left to the rules, which reject them.
+		if !set.limitsBody {
+			_ = decodeContentEncoding(c, 0)
+		}
+
+		// Report-only violations are enforced for a random sample of requests during a gradual rollout.
+		enforce := set.enforcePercentage > 0 && rand.IntN(100) < set.enforcePercentage
+
+		// The lines of an NDJSON request body are validated as separate JSON request bodies. They are split once
+		// the request-level rules, which limit and transcode the request body, have passed.
+		var lines []ndjsonLine
+		split := false
+
+		start := time.Now()
+		var reported []error
+		for i, rule := range set.rules {
+			// Request bodies with reported violations are not decoded, so Body never returns a body that violated a rule.
+			if len(reported) > 0 && isBinding(rule) {
+				continue
+			}
+			if !split && !isRequestLevel(rule) {
+				split = true
+				if bodyFormatOf(c) == formatNDJSON {
+					lines = ndjsonLines(c)
+				}
+			}
+			ruleStart := time.Now()
+			var err error
+			if lines != nil && !isRequestLevel(rule) {
+				err = restrictLines(c, rule, lines, cfg.NDJSONMaxErrors)
+			} else {
+				err = rule.Restrict(c)
+			}
+			e := Evaluation{
+				Rule:     set.names[i],
+				Fields:   set.fields[i],
+				Duration: time.Since(ruleStart),
+				Err:      err,
+				Reported: err != nil && (set.reportOnly || isReportOnly(rule)) && !enforce && !isAlwaysEnforced(rule),
+			}
+			var ve *Error
+			if errors.As(err, &ve) {
+				if ve.Rule == "" {
+					ve.Rule = set.names[i]
 				}
-				return cfg.ErrorHandler(c, err)
+				e.Field = ve.Field
 			}
+			result.addEvaluation(e)
+			if cfg.OnRuleEvaluated != nil {
+				cfg.OnRuleEvaluated(c, e)
+			}
+
+			if err == nil {
+				continue
+			}
+			if e.Reported {
+				if cfg.OnReport != nil {
+					cfg.OnReport(c, err)
+				}
+				reported = append(reported, err)
+				continue
+			}
+			if cfg.OnFailure != nil {
+				cfg.OnFailure(c, e)
+			}
+			if ve != nil && cfg.Localizer != nil && !ve.customMessage {
+				ve.Message = cfg.Localizer.Localize(ve, preferredLanguages(c, cfg)...)
+			}
+			result.finish(err, reported, time.Since(start))
+			return cfg.ErrorHandler(c, err)
 		}
 
-		if cfg.ContextKey != "" {
-			c.Locals(cfg.ContextKey, nil)
+		duration := time.Since(start)
+		result.finish(nil, reported, duration)
+		if cfg.OnSuccess != nil {
+			cfg.OnSuccess(c, duration)
 		}
 
 		return c.Next()
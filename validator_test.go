@@ -5,17 +5,43 @@
 package validator_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
 	"io"
+	"log/slog"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	validator "github.com/H0llyW00dzZ/FiberValidator"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/gofiber/fiber/v2"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
 )
 
 func TestValidatorWithDefaultErrorHandler(t *testing.T) {
@@ -126,7 +152,7 @@ func TestValidatorWithDefaultErrorHandler(t *testing.T) {
 
 			if tc.expectedError != "" {
 				if tc.contentType == fiber.MIMEApplicationJSON {
-					var jsonResp map[string]string
+					var jsonResp map[string]interface{}
 					if err := json.Unmarshal(body, &jsonResp); err != nil {
 						t.Fatalf("Unexpected error unmarshaling JSON response: %v", err)
 					}
@@ -287,7 +313,7 @@ func TestValidatorWithNext(t *testing.T) {
 			contentType:    "application/json",
 			requestBody:    `{"name":"Gøpher","email":"gopher@example.com"}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Unicode characters are not allowed in the 'name' field"}`,
+			expectedBody:   `{"error":"Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode"}`,
 		},
 		{
 			name:           "GET request - validation skipped",
@@ -388,7 +414,7 @@ func TestValidatorWithInvalidJSONBody(t *testing.T) {
 				t.Fatalf("Unexpected error reading response body: %v", err)
 			}
 
-			var jsonResp map[string]string
+			var jsonResp map[string]interface{}
 			if err := json.Unmarshal(body, &jsonResp); err != nil {
 				t.Fatalf("Unexpected error unmarshaling JSON response: %v", err)
 			}
@@ -427,19 +453,19 @@ func TestValidatorWithInvalidXMLBody(t *testing.T) {
 			name:           "Invalid XML - Incomplete body",
 			requestBody:    `<data><name>Gopher</name><email>gopher@example.com</data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedError:  `<xmlError><error>Invalid XML request body</error></xmlError>`,
+			expectedError:  `<xmlError><error>Invalid XML request body</error><code>INVALID_XML_BODY</code><rule>RestrictUnicode</rule></xmlError>`,
 		},
 		{
 			name:           "Invalid XML - Unicode in email",
 			requestBody:    `<data><name>Gopher</name><email>gøpher@example.com</email></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedError:  `<xmlError><error>Unicode characters are not allowed in the &#39;email&#39; field</error></xmlError>`,
+			expectedError:  `<xmlError><error>Unicode characters are not allowed in the &#39;email&#39; field</error><code>UNICODE_NOT_ALLOWED</code><field>email</field><rule>RestrictUnicode</rule></xmlError>`,
 		},
 		{
 			name:           "Invalid XML - Non-numeric score",
 			requestBody:    `<data><name>Gopher</name><email>gopher@example.com</email><score>abc</score></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedError:  `<xmlError><error>The &#39;score&#39; field must contain only numbers</error></xmlError>`,
+			expectedError:  `<xmlError><error>The &#39;score&#39; field must contain only numbers</error><code>NUMBER_ONLY</code><field>score</field><rule>RestrictNumberOnly</rule><params><param name="fields">score</param></params></xmlError>`,
 		},
 	}
 
@@ -508,21 +534,21 @@ func TestRestrictNumberOnly(t *testing.T) {
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"age":"abc","score":80,"seafood_price":50}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'age' field must contain only numbers"}`,
+			expectedBody:   `{"error":"The 'age' field must contain only numbers","code":"NUMBER_ONLY","field":"age","rule":"RestrictNumberOnly","params":{"fields":["age"]}}`,
 		},
 		{
 			name:           "Invalid JSON request - non-numeric score",
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"age":30,"score":"def","seafood_price":50}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'score' field must contain only numbers"}`,
+			expectedBody:   `{"error":"The 'score' field must contain only numbers","code":"NUMBER_ONLY","field":"score","rule":"RestrictNumberOnly","params":{"fields":["score"]}}`,
 		},
 		{
 			name:           "Invalid JSON request - age exceeds maximum",
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"age":120,"score":80,"seafood_price":50}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'age' field must not exceed 100"}`,
+			expectedBody:   `{"error":"The 'age' field must not exceed 100","code":"MAX_VALUE_EXCEEDED","field":"age","rule":"RestrictNumberOnly","params":{"max":100}}`,
 		},
 		{
 			name:           "Valid XML request",
@@ -536,21 +562,21 @@ func TestRestrictNumberOnly(t *testing.T) {
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><age>abc</age><score>80</score><seafood_price>50</seafood_price></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;age&#39; field must contain only numbers</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;age&#39; field must contain only numbers</error><code>NUMBER_ONLY</code><field>age</field><rule>RestrictNumberOnly</rule><params><param name="fields">age</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid XML request - non-numeric score",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><age>30</age><score>def</score><seafood_price>50</seafood_price></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;score&#39; field must contain only numbers</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;score&#39; field must contain only numbers</error><code>NUMBER_ONLY</code><field>score</field><rule>RestrictNumberOnly</rule><params><param name="fields">score</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid XML request - score exceeds maximum",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><age>30</age><score>120</score><seafood_price>50</seafood_price></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;score&#39; field must not exceed 100</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;score&#39; field must not exceed 100</error><code>MAX_VALUE_EXCEEDED</code><field>score</field><rule>RestrictNumberOnly</rule><params><param name="max">100</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid Other Content-Type - age exceeds maximum",
@@ -571,35 +597,35 @@ func TestRestrictNumberOnly(t *testing.T) {
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"age":"abc","score":"xa","seafood_price":50}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'age', 'score' field must contain only numbers"}`,
+			expectedBody:   `{"error":"The 'age', 'score' field must contain only numbers","code":"NUMBER_ONLY","rule":"RestrictNumberOnly","params":{"fields":["age","score"]}}`,
 		},
 		{
 			name:           "Invalid JSON request - non-numeric age, score, and seafood_price",
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"age":"abc","score":"def","seafood_price":"ghi"}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'age', 'score', 'seafood_price' field must contain only numbers"}`,
+			expectedBody:   `{"error":"The 'age', 'score', 'seafood_price' field must contain only numbers","code":"NUMBER_ONLY","rule":"RestrictNumberOnly","params":{"fields":["age","score","seafood_price"]}}`,
 		},
 		{
 			name:           "Invalid JSON request - age and seafood_price exceed maximum",
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"age":120,"score":80,"seafood_price":150}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'age' field must not exceed 100"}`,
+			expectedBody:   `{"error":"The 'age' field must not exceed 100","code":"MAX_VALUE_EXCEEDED","field":"age","rule":"RestrictNumberOnly","params":{"max":100}}`,
 		},
 		{
 			name:           "Invalid XML request - non-numeric score and seafood_price",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><age>30</age><score>abc</score><seafood_price>def</seafood_price></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "<xmlError><error>The &#39;score&#39;, &#39;seafood_price&#39; field must contain only numbers</error></xmlError>",
+			expectedBody:   `<xmlError><error>The &#39;score&#39;, &#39;seafood_price&#39; field must contain only numbers</error><code>NUMBER_ONLY</code><rule>RestrictNumberOnly</rule><params><param name="fields">score</param><param name="fields">seafood_price</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid XML request - age and score exceed maximum",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><age>120</age><score>150</score><seafood_price>80</seafood_price></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "<xmlError><error>The &#39;age&#39; field must not exceed 100</error></xmlError>",
+			expectedBody:   `<xmlError><error>The &#39;age&#39; field must not exceed 100</error><code>MAX_VALUE_EXCEEDED</code><field>age</field><rule>RestrictNumberOnly</rule><params><param name="max">100</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid Other Content-Type - seafood_price exceeds maximum",
@@ -672,14 +698,14 @@ func TestRestrictNumberWithMaxDigitsOnly(t *testing.T) {
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"grilled_fish":1234,"lobster_roll":80,"seafood_platter":50,"bali_juice":10}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'grilled_fish' field must not exceed 3 digits"}`,
+			expectedBody:   `{"error":"The 'grilled_fish' field must not exceed 3 digits","code":"MAX_DIGITS_EXCEEDED","field":"grilled_fish","rule":"RestrictNumberOnly","params":{"max":3}}`,
 		},
 		{
 			name:           "Invalid XML request - lobster_roll exceeds maximum digits",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><grilled_fish>30</grilled_fish><lobster_roll>1234</lobster_roll><seafood_platter>50</seafood_platter><bali_juice>10</bali_juice></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;lobster_roll&#39; field must not exceed 3 digits</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;lobster_roll&#39; field must not exceed 3 digits</error><code>MAX_DIGITS_EXCEEDED</code><field>lobster_roll</field><rule>RestrictNumberOnly</rule><params><param name="max">3</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid Other Content-Type - seafood_platter exceeds maximum digits",
@@ -693,14 +719,14 @@ func TestRestrictNumberWithMaxDigitsOnly(t *testing.T) {
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"grilled_fish":1234,"lobster_roll":5678,"seafood_platter":50,"bali_juice":10}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'grilled_fish' field must not exceed 3 digits"}`,
+			expectedBody:   `{"error":"The 'grilled_fish' field must not exceed 3 digits","code":"MAX_DIGITS_EXCEEDED","field":"grilled_fish","rule":"RestrictNumberOnly","params":{"max":3}}`,
 		},
 		{
 			name:           "Invalid XML request - grilled_fish, lobster_roll, and seafood_platter exceed maximum digits",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><grilled_fish>1234</grilled_fish><lobster_roll>5678</lobster_roll><seafood_platter>9012</seafood_platter><bali_juice>10</bali_juice></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;grilled_fish&#39; field must not exceed 3 digits</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;grilled_fish&#39; field must not exceed 3 digits</error><code>MAX_DIGITS_EXCEEDED</code><field>grilled_fish</field><rule>RestrictNumberOnly</rule><params><param name="max">3</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid Other Content-Type - grilled_fish and seafood_platter exceed maximum digits",
@@ -714,14 +740,14 @@ func TestRestrictNumberWithMaxDigitsOnly(t *testing.T) {
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"grilled_fish":30,"lobster_roll":80,"seafood_platter":50,"bali_juice":1234}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'bali_juice' field must not exceed 3 digits"}`,
+			expectedBody:   `{"error":"The 'bali_juice' field must not exceed 3 digits","code":"MAX_DIGITS_EXCEEDED","field":"bali_juice","rule":"RestrictNumberOnly","params":{"max":3}}`,
 		},
 		{
 			name:           "Invalid XML request - bali_juice exceeds maximum digits",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><grilled_fish>30</grilled_fish><lobster_roll>80</lobster_roll><seafood_platter>50</seafood_platter><bali_juice>1234</bali_juice></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;bali_juice&#39; field must not exceed 3 digits</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;bali_juice&#39; field must not exceed 3 digits</error><code>MAX_DIGITS_EXCEEDED</code><field>bali_juice</field><rule>RestrictNumberOnly</rule><params><param name="max">3</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid Other Content-Type - bali_juice exceeds maximum digits",
@@ -774,8 +800,8 @@ func TestValidatorWithContextKey(t *testing.T) {
 	}))
 
 	app.Post("/", func(c *fiber.Ctx) error {
-		validationResult := c.Locals("validationResult")
-		if validationResult != nil {
+		result, ok := c.Locals("validationResult").(*validator.Result)
+		if !ok || !result.Passed {
 			return c.Status(fiber.StatusBadRequest).SendString("Validation failed")
 		}
 		return c.SendString("OK")
@@ -865,28 +891,28 @@ func TestRestrictStringLength(t *testing.T) {
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"name":"Gopher with a very long name that exceeds the maximum length","description":"A short description"}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'name' field must not exceed 50 characters"}`,
+			expectedBody:   `{"error":"The 'name' field must not exceed 50 characters","code":"MAX_LENGTH_EXCEEDED","field":"name","rule":"RestrictStringLength","params":{"max":50}}`,
 		},
 		{
 			name:           "Invalid JSON request - description exceeds maximum length",
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"name":"Gopher","description":"A very long description that exceeds the maximum length limit"}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'description' field must not exceed 50 characters"}`,
+			expectedBody:   `{"error":"The 'description' field must not exceed 50 characters","code":"MAX_LENGTH_EXCEEDED","field":"description","rule":"RestrictStringLength","params":{"max":50}}`,
 		},
 		{
 			name:           "Invalid JSON request - invalid JSON body",
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"name":"Gopher","description":"A short description"`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Invalid JSON request body"}`,
+			expectedBody:   `{"error":"Invalid JSON request body","code":"INVALID_JSON_BODY","rule":"RestrictStringLength"}`,
 		},
 		{
 			name:           "Invalid JSON request - multiple fields exceed maximum length",
 			contentType:    fiber.MIMEApplicationJSON,
 			requestBody:    `{"name":"Gopher with a very long name that exceeds the maximum length","description":"A very long description that exceeds the maximum length limit"}`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"The 'name' field must not exceed 50 characters"}`,
+			expectedBody:   `{"error":"The 'name' field must not exceed 50 characters","code":"MAX_LENGTH_EXCEEDED","field":"name","rule":"RestrictStringLength","params":{"max":50}}`,
 		},
 		{
 			name:           "Valid XML request",
@@ -900,28 +926,28 @@ func TestRestrictStringLength(t *testing.T) {
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><name>Gopher with a very long name that exceeds the maximum length</name><description>A short description</description></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;name&#39; field must not exceed 50 characters</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;name&#39; field must not exceed 50 characters</error><code>MAX_LENGTH_EXCEEDED</code><field>name</field><rule>RestrictStringLength</rule><params><param name="max">50</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid XML request - description exceeds maximum length",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><name>Gopher</name><description>A very long description that exceeds the maximum length limit</description></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;description&#39; field must not exceed 50 characters</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;description&#39; field must not exceed 50 characters</error><code>MAX_LENGTH_EXCEEDED</code><field>description</field><rule>RestrictStringLength</rule><params><param name="max">50</param></params></xmlError>`,
 		},
 		{
 			name:           "Invalid XML request - invalid XML body",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><name>Gopher</name><description>A short description</description>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>Invalid XML request body</error></xmlError>`,
+			expectedBody:   `<xmlError><error>Invalid XML request body</error><code>INVALID_XML_BODY</code><rule>RestrictStringLength</rule></xmlError>`,
 		},
 		{
 			name:           "Invalid XML request - multiple fields exceed maximum length",
 			contentType:    fiber.MIMEApplicationXML,
 			requestBody:    `<data><name>Gopher with a very long name that exceeds the maximum length</name><description>A very long description that exceeds the maximum length limit</description></data>`,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `<xmlError><error>The &#39;name&#39; field must not exceed 50 characters</error></xmlError>`,
+			expectedBody:   `<xmlError><error>The &#39;name&#39; field must not exceed 50 characters</error><code>MAX_LENGTH_EXCEEDED</code><field>name</field><rule>RestrictStringLength</rule><params><param name="max">50</param></params></xmlError>`,
 		},
 		{
 			name:           "Valid Other Content-Type request",
@@ -978,3 +1004,4178 @@ func TestRestrictStringLength(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONLimits(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+		},
+		JSONLimits: &validator.JSONLimits{
+			MaxDepth:            ptr(3),
+			MaxKeys:             ptr(4),
+			MaxArrayLength:      ptr(3),
+			MaxStringLength:     ptr(16),
+			CaseInsensitiveKeys: true,
+		},
+	}))
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid JSON request",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"Gopher","tags":["a","b"],"meta":{"x":{"y":1}}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Invalid JSON request - duplicate key",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"Gopher","name":"Gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Duplicate key 'name' in JSON request body","code":"DUPLICATE_JSON_KEY","field":"name","rule":"JSONLimits"}`,
+		},
+		{
+			name:           "Invalid JSON request - duplicate key with different case",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"Gopher","NAME":"Gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Duplicate key 'NAME' in JSON request body","code":"DUPLICATE_JSON_KEY","field":"NAME","rule":"JSONLimits"}`,
+		},
+		{
+			name:           "Valid JSON request - same key in sibling objects",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"a":{"id":1},"b":{"id":2}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Invalid JSON request - nesting too deep",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"a":{"b":{"c":{"d":1}}}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"JSON request body must not exceed a nesting depth of 3","code":"JSON_MAX_DEPTH_EXCEEDED","rule":"JSONLimits","params":{"max":3}}`,
+		},
+		{
+			name:           "Invalid JSON request - too many keys",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"a":1,"b":2,"c":3,"d":4,"e":5}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"JSON objects must not contain more than 4 keys","code":"JSON_MAX_KEYS_EXCEEDED","rule":"JSONLimits","params":{"max":4}}`,
+		},
+		{
+			name:           "Invalid JSON request - array too long",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":[1,[2],{"x":3},"4"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"JSON arrays must not contain more than 3 elements","code":"JSON_MAX_ARRAY_LENGTH_EXCEEDED","rule":"JSONLimits","params":{"max":3}}`,
+		},
+		{
+			name:           "Invalid JSON request - string too long",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"Gopher with a very long name"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"JSON strings must not exceed 16 bytes","code":"JSON_MAX_STRING_LENGTH_EXCEEDED","rule":"JSONLimits","params":{"max":16}}`,
+		},
+		{
+			name:           "Invalid JSON request - trailing document",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"Gopher"}{"name":"Gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid JSON request body","code":"INVALID_JSON_BODY","rule":"JSONLimits"}`,
+		},
+		{
+			name:           "Invalid JSON request - unterminated object",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"Gopher"`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid JSON request body","code":"INVALID_JSON_BODY","rule":"JSONLimits"}`,
+		},
+		{
+			name:           "Valid XML request - limits only apply to JSON",
+			contentType:    fiber.MIMEApplicationXML,
+			requestBody:    `<data><name>Gopher</name><name>Gopher</name></data>`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestValidatorWithMaxBodySize(t *testing.T) {
+	app := fiber.New()
+
+	app.Post("/login", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"username"},
+			},
+		},
+		MaxBodySize: 32,
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	app.Post("/upload", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictStringLength{
+				Fields:      []string{"description"},
+				MaxLength:   ptr(64),
+				MaxBodySize: 48,
+			},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	app.Post("/compressed", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"username"},
+			},
+		},
+		MaxBodySize: 64,
+	}), func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	testCases := []struct {
+		name            string
+		path            string
+		contentType     string
+		contentEncoding string
+		requestBody     string
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:           "Valid JSON request - within config limit",
+			path:           "/login",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"username":"gopher"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Invalid JSON request - exceeds config limit",
+			path:           "/login",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"username":"gopher","password":"secret-password"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request body must not exceed 32 bytes","code":"BODY_TOO_LARGE","rule":"MaxBodySize","params":{"max":32}}`,
+		},
+		{
+			name:           "Invalid XML request - exceeds config limit",
+			path:           "/login",
+			contentType:    fiber.MIMEApplicationXML,
+			requestBody:    `<data><username>gopher</username></data>`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `<xmlError><error>Request body must not exceed 32 bytes</error><code>BODY_TOO_LARGE</code><rule>MaxBodySize</rule><params><param name="max">32</param></params></xmlError>`,
+		},
+		{
+			name:           "Valid JSON request - within rule limit",
+			path:           "/upload",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"description":"A short description"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Invalid JSON request - exceeds rule limit",
+			path:           "/upload",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"description":"A description that is longer than the limit"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request body must not exceed 48 bytes","code":"BODY_TOO_LARGE","rule":"RestrictStringLength","params":{"max":48}}`,
+		},
+		{
+			name:            "Compressed JSON request - decoded within config limit",
+			path:            "/compressed",
+			contentType:     fiber.MIMEApplicationJSON,
+			contentEncoding: "gzip",
+			requestBody:     gzipString(t, `{"username":"gopher"}`),
+			expectedStatus:  http.StatusOK,
+			expectedBody:    `{"username":"gopher"}`,
+		},
+		{
+			name:            "Invalid compressed JSON request - decoded within config limit",
+			path:            "/compressed",
+			contentType:     fiber.MIMEApplicationJSON,
+			contentEncoding: "gzip",
+			requestBody:     gzipString(t, `{"username":"gøpher"}`),
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    `{"error":"Unicode characters are not allowed in the 'username' field","code":"UNICODE_NOT_ALLOWED","field":"username","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:            "Compressed JSON request - expands beyond config limit",
+			path:            "/login",
+			contentType:     fiber.MIMEApplicationJSON,
+			contentEncoding: "gzip",
+			requestBody:     gzipString(t, `{"username":"`+strings.Repeat("a", 1<<20)+`"}`),
+			expectedStatus:  http.StatusRequestEntityTooLarge,
+			expectedBody:    `{"error":"Request body must not exceed 32 bytes","code":"BODY_TOO_LARGE","rule":"MaxBodySize","params":{"max":32}}`,
+		},
+		{
+			name:            "Compressed JSON request - expands beyond rule limit",
+			path:            "/upload",
+			contentType:     fiber.MIMEApplicationJSON,
+			contentEncoding: "gzip",
+			requestBody:     gzipString(t, `{"description":"`+strings.Repeat("a", 1<<20)+`"}`),
+			expectedStatus:  http.StatusRequestEntityTooLarge,
+			expectedBody:    `{"error":"Request body must not exceed 48 bytes","code":"BODY_TOO_LARGE","rule":"RestrictStringLength","params":{"max":48}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			if tc.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", tc.contentEncoding)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+// gzipString compresses a string with gzip.
+func gzipString(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(w, s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestValidatorWithReportOnly(t *testing.T) {
+	app := fiber.New()
+
+	var reported []string
+	reportOnly := validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+			validator.RestrictNumberOnly{
+				Fields: []string{"age"},
+			},
+		},
+		ReportOnly: true,
+		ContextKey: "validationResult",
+		OnReport: func(c *fiber.Ctx, err error) {
+			reported = append(reported, err.Error())
+		},
+	})
+
+	perRule := validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields:     []string{"name"},
+				ReportOnly: true,
+			},
+			validator.RestrictNumberOnly{
+				Fields: []string{"age"},
+			},
+		},
+	})
+
+	enforced := validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+		},
+		ReportOnly:        true,
+		EnforcePercentage: 100,
+	})
+
+	safety := validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+		},
+		MaxBodySize: 32,
+		JSONLimits:  &validator.JSONLimits{},
+		ReportOnly:  true,
+	})
+
+	handler := func(c *fiber.Ctx) error {
+		if result := validator.ResultFrom(c); len(result.Reported) > 0 {
+			return c.SendString("Reported: " + errors.Join(result.Reported...).Error())
+		}
+		return c.SendString("OK")
+	}
+
+	app.Post("/report", reportOnly, handler)
+	app.Post("/rule", perRule, handler)
+	app.Post("/enforced", enforced, handler)
+	app.Post("/safety", safety, handler)
+
+	testCases := []struct {
+		name           string
+		path           string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+		expectedReport []string
+	}{
+		{
+			name:           "Report only - valid request",
+			path:           "/report",
+			requestBody:    `{"name":"Gopher","age":30}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Report only - violations are reported and the request continues",
+			path:           "/report",
+			requestBody:    `{"name":"Gøpher","age":"abc"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Reported: Unicode characters are not allowed in the 'name' field\nThe 'age' field must contain only numbers",
+			expectedReport: []string{
+				"Unicode characters are not allowed in the 'name' field",
+				"The 'age' field must contain only numbers",
+			},
+		},
+		{
+			name:           "Per-rule report only - reported rule continues",
+			path:           "/rule",
+			requestBody:    `{"name":"Gøpher","age":30}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Reported: Unicode characters are not allowed in the 'name' field",
+		},
+		{
+			name:           "Per-rule report only - other rules are enforced",
+			path:           "/rule",
+			requestBody:    `{"name":"Gøpher","age":"abc"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'age' field must contain only numbers","code":"NUMBER_ONLY","field":"age","rule":"RestrictNumberOnly","params":{"fields":["age"]}}`,
+		},
+		{
+			name:           "Report only - fully enforced rollout",
+			path:           "/enforced",
+			requestBody:    `{"name":"Gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:           "Report only - body size limit is enforced",
+			path:           "/safety",
+			requestBody:    `{"name":"Gopher","bio":"a very long biography"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request body must not exceed 32 bytes","code":"BODY_TOO_LARGE","rule":"MaxBodySize","params":{"max":32}}`,
+		},
+		{
+			name:           "Report only - JSON limits are enforced",
+			path:           "/safety",
+			requestBody:    `{"name":"Go","name":"Gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Duplicate key 'name' in JSON request body","code":"DUPLICATE_JSON_KEY","field":"name","rule":"JSONLimits"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reported = nil
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+
+			if strings.Join(reported, "|") != strings.Join(tc.expectedReport, "|") {
+				t.Errorf("Expected reported violations %q, got %q", tc.expectedReport, reported)
+			}
+		})
+	}
+}
+
+func TestValidatorWithObservabilityHooks(t *testing.T) {
+	app := fiber.New()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	metrics := validator.NewMetrics()
+
+	var (
+		evaluated []validator.Evaluation
+		succeeded int
+		failed    []validator.Evaluation
+	)
+	slogHook := validator.SlogHook(logger)
+
+	app.Get("/metrics", metrics.Handler())
+
+	app.Use(validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+			validator.RestrictNumberOnly{
+				Fields: []string{"age"},
+			},
+		},
+		MaxBodySize: 1024,
+		OnRuleEvaluated: func(c *fiber.Ctx, e validator.Evaluation) {
+			evaluated = append(evaluated, e)
+			slogHook(c, e)
+			metrics.OnRuleEvaluated(c, e)
+		},
+		OnSuccess: func(c *fiber.Ctx, duration time.Duration) {
+			succeeded++
+		},
+		OnFailure: func(c *fiber.Ctx, e validator.Evaluation) {
+			failed = append(failed, e)
+		},
+	}))
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		requestBody    string
+		expectedStatus int
+		expectedRules  []string
+		expectedFailed string
+	}{
+		{
+			name:           "Valid request",
+			requestBody:    `{"name":"Gopher","age":30}`,
+			expectedStatus: http.StatusOK,
+			expectedRules:  []string{"MaxBodySize", "RestrictUnicode", "RestrictNumberOnly"},
+		},
+		{
+			name:           "Invalid request - Unicode in name",
+			requestBody:    `{"name":"Gøpher","age":30}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedRules:  []string{"MaxBodySize", "RestrictUnicode"},
+			expectedFailed: "RestrictUnicode",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluated, succeeded, failed = nil, 0, nil
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			var rules []string
+			for _, e := range evaluated {
+				rules = append(rules, e.Rule)
+			}
+			if strings.Join(rules, ",") != strings.Join(tc.expectedRules, ",") {
+				t.Errorf("Expected evaluated rules %v, got %v", tc.expectedRules, rules)
+			}
+
+			if tc.expectedFailed == "" {
+				if succeeded != 1 || len(failed) != 0 {
+					t.Errorf("Expected one success and no failures, got %d successes and %d failures", succeeded, len(failed))
+				}
+				return
+			}
+
+			if succeeded != 0 || len(failed) != 1 {
+				t.Fatalf("Expected no successes and one failure, got %d successes and %d failures", succeeded, len(failed))
+			}
+			if failed[0].Rule != tc.expectedFailed || failed[0].Err == nil {
+				t.Errorf("Expected failure of rule %s, got %+v", tc.expectedFailed, failed[0])
+			}
+			if strings.Join(failed[0].Fields, ",") != "name" {
+				t.Errorf("Expected failed fields [name], got %v", failed[0].Fields)
+			}
+		})
+	}
+
+	if !strings.Contains(logs.String(), `level=WARN msg="validator rule evaluated" rule=RestrictUnicode fields=[name] outcome=failed`) {
+		t.Errorf("Expected a structured log entry for the failed rule, got:\n%s", logs.String())
+	}
+
+	if got := metrics.Count("RestrictUnicode", "passed"); got != 1 {
+		t.Errorf("Expected 1 passed evaluation of RestrictUnicode, got %d", got)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error reading response body: %v", err)
+	}
+
+	for _, want := range []string{
+		"# TYPE validator_rule_evaluations_total counter",
+		`validator_rule_evaluations_total{rule="RestrictUnicode",outcome="failed"} 1`,
+		`validator_rule_evaluations_total{rule="RestrictUnicode",outcome="passed"} 1`,
+		`validator_rule_evaluations_total{rule="RestrictNumberOnly",outcome="passed"} 1`,
+		"# TYPE validator_rule_duration_seconds histogram",
+		`validator_rule_duration_seconds_bucket{rule="MaxBodySize",le="+Inf"} 2`,
+		`validator_rule_duration_seconds_count{rule="RestrictUnicode"} 2`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected metrics output to contain %q, got:\n%s", want, body)
+		}
+	}
+
+	t.Run("Request context", func(t *testing.T) {
+		recorder := &contextRecorder{}
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.SetUserContext(context.WithValue(c.UserContext(), traceIDKey{}, "trace-1"))
+			return c.Next()
+		})
+		app.Post("/", validator.New(validator.Config{
+			Rules:           []validator.Restrictor{validator.RestrictUnicode{Fields: []string{"name"}}},
+			OnRuleEvaluated: validator.SlogHook(slog.New(recorder)),
+		}), func(c *fiber.Ctx) error {
+			return c.SendString("OK")
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Gopher"}`))
+		req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if recorder.traceID != "trace-1" {
+			t.Errorf("Expected the trace ID of the request context, got %v", recorder.traceID)
+		}
+	})
+}
+
+// traceIDKey is the context key of the trace ID used to test SlogHook.
+type traceIDKey struct{}
+
+// contextRecorder is a slog.Handler that records the trace ID of the context of the last record.
+type contextRecorder struct {
+	traceID interface{}
+}
+
+func (h *contextRecorder) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *contextRecorder) Handle(ctx context.Context, _ slog.Record) error {
+	h.traceID = ctx.Value(traceIDKey{})
+	return nil
+}
+
+func (h *contextRecorder) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *contextRecorder) WithGroup(string) slog.Handler { return h }
+
+func TestValidatorWithLocalizer(t *testing.T) {
+	app := fiber.New()
+
+	localizer := validator.NewLocalizer()
+	if err := localizer.Register(language.German, validator.CodeMaxLengthExceeded, "Feld '%[1]s': maximal %[2]d Zeichen"); err != nil {
+		t.Fatalf("Unexpected error registering translation: %v", err)
+	}
+	if err := localizer.Register(language.French, validator.CodeUnicodeNotAllowed, "Les caractères Unicode ne sont pas autorisés dans le champ '%[1]s'"); err != nil {
+		t.Fatalf("Unexpected error registering translation: %v", err)
+	}
+
+	app.Use(validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+			validator.RestrictNumberOnly{
+				Fields: []string{"age"},
+				Max:    ptr(100),
+			},
+			validator.RestrictStringLength{
+				Fields:    []string{"description"},
+				MaxLength: ptr(10),
+			},
+		},
+		Localizer: localizer,
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		},
+	}))
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		acceptLanguage string
+		requestBody    string
+		expectedBody   string
+	}{
+		{
+			name:         "No Accept-Language",
+			requestBody:  `{"name":"Gøpher"}`,
+			expectedBody: "Unicode characters are not allowed in the 'name' field",
+		},
+		{
+			name:           "Unsupported language falls back to English",
+			acceptLanguage: "ko-KR",
+			requestBody:    `{"name":"Gøpher"}`,
+			expectedBody:   "Unicode characters are not allowed in the 'name' field",
+		},
+		{
+			name:           "Indonesian",
+			acceptLanguage: "id-ID,id;q=0.9,en;q=0.8",
+			requestBody:    `{"name":"Gøpher"}`,
+			expectedBody:   "Karakter Unicode tidak diperbolehkan pada kolom 'name'",
+		},
+		{
+			name:           "Japanese",
+			acceptLanguage: "ja",
+			requestBody:    `{"age":120}`,
+			expectedBody:   "'age' フィールドは 100 以下である必要があります",
+		},
+		{
+			name:           "German with overridden translation",
+			acceptLanguage: "de-CH, en;q=0.5",
+			requestBody:    `{"description":"A long description"}`,
+			expectedBody:   "Feld 'description': maximal 10 Zeichen",
+		},
+		{
+			name:           "Registered language",
+			acceptLanguage: "fr",
+			requestBody:    `{"name":"Gøpher"}`,
+			expectedBody:   "Les caractères Unicode ne sont pas autorisés dans le champ 'name'",
+		},
+		{
+			name:           "Registered language without translation for the code",
+			acceptLanguage: "fr",
+			requestBody:    `{"age":120}`,
+			expectedBody:   "The 'age' field must not exceed 100",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+			if tc.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestRestrictorWithErrorOverrides(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictNumberOnly{
+				Fields:    []string{"phone", "age"},
+				MaxDigits: ptr(12),
+				Status:    fiber.StatusUnprocessableEntity,
+				FieldOverrides: map[string]validator.ErrorOverride{
+					"phone": {Message: "Phone number should be at most {max} digits"},
+				},
+			},
+			validator.RestrictStringLength{
+				Fields:    []string{"nickname"},
+				MaxLength: ptr(8),
+				Message:   "'{value}' is too long for {field}, the limit is {limit}",
+			},
+			validator.RestrictUnicode{
+				Fields: []string{"username"},
+				FieldOverrides: map[string]validator.ErrorOverride{
+					"username": {Status: fiber.StatusConflict},
+				},
+			},
+			validator.RestrictNumberOnly{
+				Fields: []string{"zip", "pin"},
+				FieldOverrides: map[string]validator.ErrorOverride{
+					"pin": {Message: "{field} must be numeric", Status: fiber.StatusConflict},
+				},
+			},
+		},
+		Localizer: validator.NewLocalizer(),
+	}))
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid request",
+			requestBody:    `{"phone":"628123456789","age":30,"nickname":"gopher","username":"gopher"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Per-field message and rule status",
+			requestBody:    `{"phone":"62812345678901"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Phone number should be at most 12 digits","code":"MAX_DIGITS_EXCEEDED","field":"phone","rule":"RestrictNumberOnly","params":{"max":12}}`,
+		},
+		{
+			name:           "Rule status with localized message",
+			requestBody:    `{"age":"abc"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Das Feld 'age' darf nur Ziffern enthalten","code":"NUMBER_ONLY","field":"age","rule":"RestrictNumberOnly","params":{"fields":["age"]}}`,
+		},
+		{
+			name:           "Rule message with value and limit",
+			requestBody:    `{"nickname":"gopher-the-great"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"'gopher-the-great' is too long for nickname, the limit is 8","code":"MAX_LENGTH_EXCEEDED","field":"nickname","rule":"RestrictStringLength","params":{"max":8}}`,
+		},
+		{
+			name:           "Per-field status",
+			requestBody:    `{"username":"gøpher"}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Unicode-Zeichen sind im Feld 'username' nicht erlaubt","code":"UNICODE_NOT_ALLOWED","field":"username","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:           "Per-field override of a violation of several fields",
+			requestBody:    `{"zip":"abc","pin":"xyz"}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"zip, pin must be numeric","code":"NUMBER_ONLY","rule":"RestrictNumberOnly","params":{"fields":["zip","pin"]}}`,
+		},
+		{
+			name:           "Invalid body is not overridden",
+			requestBody:    `{"phone":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Ungültiger JSON-Anfragetext","code":"INVALID_JSON_BODY","rule":"RestrictNumberOnly"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+			req.Header.Set("Accept-Language", "de")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestValidatorErrorsIsAs(t *testing.T) {
+	app := fiber.New()
+
+	var captured error
+	app.Use(validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+			validator.RestrictNumberOnly{
+				Fields: []string{"age", "score"},
+			},
+			validator.RestrictStringLength{
+				Fields:    []string{"bio"},
+				MaxLength: ptr(8),
+			},
+		},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			captured = err
+			return validator.DefaultErrorHandler(c, fmt.Errorf("wrapped: %w", err))
+		},
+	}))
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedIs     error
+		expectedFields []string
+		expectedCause  bool
+	}{
+		{
+			name:           "Unicode in name",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"Gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedIs:     validator.ErrUnicodeNotAllowed,
+			expectedFields: []string{"name"},
+		},
+		{
+			name:           "Non-numeric age and score",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"age":"abc","score":"def"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedIs:     validator.ErrNotNumeric,
+			expectedFields: []string{"age", "score"},
+		},
+		{
+			name:           "Bio exceeds maximum length",
+			contentType:    fiber.MIMEApplicationXML,
+			requestBody:    `<data><bio>A long biography</bio></data>`,
+			expectedStatus: http.StatusBadRequest,
+			expectedIs:     validator.ErrMaxLengthExceeded,
+			expectedFields: []string{"bio"},
+		},
+		{
+			name:           "Invalid JSON body",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedIs:     validator.ErrInvalidBody,
+			expectedCause:  true,
+		},
+		{
+			name:           "Invalid XML body",
+			contentType:    fiber.MIMEApplicationXML,
+			requestBody:    `<data><name>Gopher</data>`,
+			expectedStatus: http.StatusBadRequest,
+			expectedIs:     validator.ErrInvalidBody,
+			expectedCause:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			captured = nil
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			if !errors.Is(captured, tc.expectedIs) {
+				t.Errorf("Expected error to match %v, got %v", tc.expectedIs, captured)
+			}
+
+			var ve *validator.Error
+			if !errors.As(captured, &ve) {
+				t.Fatalf("Expected a *validator.Error, got %T", captured)
+			}
+
+			var fields []string
+			if joined, ok := ve.Err.(interface{ Unwrap() []error }); ok {
+				for _, err := range joined.Unwrap() {
+					var fe *validator.FieldError
+					if errors.As(err, &fe) {
+						fields = append(fields, fe.Field)
+					}
+				}
+			} else {
+				var fe *validator.FieldError
+				if errors.As(captured, &fe) {
+					fields = append(fields, fe.Field)
+				}
+			}
+			if strings.Join(fields, ",") != strings.Join(tc.expectedFields, ",") {
+				t.Errorf("Expected field errors for %v, got %v", tc.expectedFields, fields)
+			}
+
+			var be *validator.BodyParseError
+			if errors.As(captured, &be) != tc.expectedCause {
+				t.Errorf("Expected BodyParseError to be %v, got %v", tc.expectedCause, captured)
+			}
+			if tc.expectedCause && be.Err == nil {
+				t.Errorf("Expected BodyParseError to carry the decode error")
+			}
+		})
+	}
+}
+
+type bindLogin struct {
+	Username string `json:"username" xml:"username" form:"username"`
+	Age      int    `json:"age" xml:"age" form:"age"`
+}
+
+func TestBind(t *testing.T) {
+	app := fiber.New()
+
+	var handled int
+	app.Post("/", validator.Bind[bindLogin](validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"username"},
+			},
+		},
+	}), func(c *fiber.Ctx) error {
+		handled++
+		login := validator.Body[bindLogin](c)
+		if login == nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("missing body")
+		}
+		if validator.Body[string](c) != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("unexpected body type")
+		}
+		return c.SendString(fmt.Sprintf("%s:%d", login.Username, login.Age))
+	})
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+		expectHandled  bool
+	}{
+		{
+			name:           "Valid JSON request",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"username":"gopher","age":30}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "gopher:30",
+			expectHandled:  true,
+		},
+		{
+			name:           "Valid XML request",
+			contentType:    fiber.MIMEApplicationXML,
+			requestBody:    `<login><username>gopher</username><age>30</age></login>`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "gopher:30",
+			expectHandled:  true,
+		},
+		{
+			name:           "Rejected JSON request never reaches the handler",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"username":"gøpher","age":30}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Unicode characters are not allowed in the 'username' field","code":"UNICODE_NOT_ALLOWED","field":"username","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:           "JSON request that does not fit the struct",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"username":"gopher","age":"thirty"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid JSON request body","code":"INVALID_JSON_BODY","rule":"Bind"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handled = 0
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+
+			if (handled == 1) != tc.expectHandled {
+				t.Errorf("Expected handler to be called: %v, called %d times", tc.expectHandled, handled)
+			}
+		})
+	}
+}
+
+func TestBindReportOnly(t *testing.T) {
+	app := fiber.New()
+
+	app.Post("/", validator.Bind[bindLogin](validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"username"}},
+		},
+		ReportOnly: true,
+	}), func(c *fiber.Ctx) error {
+		login := validator.Body[bindLogin](c)
+		if login == nil {
+			return c.SendString("not bound")
+		}
+		return c.SendString(login.Username)
+	})
+
+	testCases := []struct {
+		name         string
+		requestBody  string
+		expectedBody string
+	}{
+		{
+			name:         "Valid request is bound",
+			requestBody:  `{"username":"gopher","age":30}`,
+			expectedBody: "gopher",
+		},
+		{
+			name:         "Reported request reaches the handler without being bound",
+			requestBody:  `{"username":"gøpher","age":30}`,
+			expectedBody: "not bound",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestBindDecodesOnce(t *testing.T) {
+	var decoded int
+	app := fiber.New(fiber.Config{
+		JSONDecoder: func(data []byte, v interface{}) error {
+			decoded++
+			return json.Unmarshal(data, v)
+		},
+	})
+
+	app.Post("/", validator.Bind[bindLogin](validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"username"}},
+			validator.RestrictStringLength{Fields: []string{"username"}, MaxLength: ptr(16)},
+			validator.RestrictNumberOnly{Fields: []string{"age"}},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString(validator.Body[bindLogin](c).Username)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"gopher","age":30}`))
+	req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	// The rules share one decoded document, and the struct is decoded once more.
+	if decoded != 2 {
+		t.Errorf("Expected the body to be decoded 2 times, got %d", decoded)
+	}
+}
+
+func TestValidatorResult(t *testing.T) {
+	app := fiber.New()
+
+	summarize := func(result *validator.Result) string {
+		if result == nil {
+			return "no result"
+		}
+		var rules, fields []string
+		for _, e := range result.Rules {
+			rules = append(rules, e.Rule+":"+e.Outcome())
+		}
+		for _, f := range result.Fields {
+			fields = append(fields, fmt.Sprintf("%s:%s:%v", f.Field, f.Rule, f.Passed()))
+		}
+		return fmt.Sprintf("passed=%v rules=%s fields=%s values=%v reported=%d",
+			result.Passed, strings.Join(rules, ","), strings.Join(fields, ","), result.Values, len(result.Reported))
+	}
+
+	maxLength := 5
+	app.Use(validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+			validator.RestrictStringLength{
+				Fields:     []string{"name"},
+				MaxLength:  &maxLength,
+				ReportOnly: true,
+			},
+			validator.RestrictNumberOnly{
+				Fields: []string{"age"},
+			},
+		},
+		ContextKey:   "validationResult",
+		RecordValues: true,
+		Next: func(c *fiber.Ctx) bool {
+			return c.Path() == "/skip"
+		},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return c.Status(fiber.StatusBadRequest).SendString(summarize(validator.ResultFrom(c)))
+		},
+	}))
+
+	handler := func(c *fiber.Ctx) error {
+		if result := validator.ResultFrom(c); result != nil && c.Locals("validationResult") != result {
+			return c.Status(fiber.StatusInternalServerError).SendString("ContextKey does not hold the result")
+		}
+		return c.SendString(summarize(validator.ResultFrom(c)))
+	}
+	app.Post("/", handler)
+	app.Post("/skip", handler)
+	app.Post("/unrecorded", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+		},
+		ContextKey: "validationResult",
+	}), handler)
+	app.Post("/sanitized", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{
+				Fields: []string{"bio", "user_password", "API-Key"},
+			},
+		},
+		ContextKey:   "validationResult",
+		RecordValues: true,
+	}), handler)
+
+	testCases := []struct {
+		name           string
+		path           string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Passed request",
+			path:           "/",
+			requestBody:    `{"name":"Go","age":30}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "passed=true rules=RestrictUnicode:passed,RestrictStringLength:passed,RestrictNumberOnly:passed fields=name:RestrictUnicode:true,name:RestrictStringLength:true,age:RestrictNumberOnly:true values=map[age:30 name:Go] reported=0",
+		},
+		{
+			name:           "Passed request with a reported violation",
+			path:           "/",
+			requestBody:    `{"name":"Gopher","age":30}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "passed=true rules=RestrictUnicode:passed,RestrictStringLength:reported,RestrictNumberOnly:passed fields=name:RestrictUnicode:true,name:RestrictStringLength:false,age:RestrictNumberOnly:true values=map[age:30] reported=1",
+		},
+		{
+			name:           "Failed request",
+			path:           "/",
+			requestBody:    `{"name":"Go","age":"abc"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "passed=false rules=RestrictUnicode:passed,RestrictStringLength:passed,RestrictNumberOnly:failed fields=name:RestrictUnicode:true,name:RestrictStringLength:true,age:RestrictNumberOnly:false values=map[name:Go] reported=0",
+		},
+		{
+			name:           "Values are not recorded by default",
+			path:           "/unrecorded",
+			requestBody:    `{"name":"Go","age":30}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "passed=true rules=RestrictUnicode:passed fields=name:RestrictUnicode:true values=map[] reported=0",
+		},
+		{
+			name:           "Recorded values are truncated and masked",
+			path:           "/sanitized",
+			requestBody:    `{"bio":"` + strings.Repeat("a", 70) + `","user_password":"hunter2","API-Key":"abc123"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "passed=true rules=RestrictUnicode:passed fields=bio:RestrictUnicode:true,user_password:RestrictUnicode:true,API-Key:RestrictUnicode:true values=map[API-Key:[REDACTED] bio:" + strings.Repeat("a", 64) + "… user_password:[REDACTED]] reported=0",
+		},
+		{
+			name:           "Skipped request has no result",
+			path:           "/skip",
+			requestBody:    `{"name":"Gøpher"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "no result",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	app := fiber.New()
+
+	maxBodySize := 64
+	registry := validator.NewRegistry(validator.Config{
+		MaxBodySize: maxBodySize,
+	})
+	registry.
+		Add(fiber.MethodPost, "/login", validator.RestrictUnicode{Fields: []string{"username"}}).
+		Add(fiber.MethodPost, "/users/:id", validator.RestrictNumberOnly{Fields: []string{"age"}}).
+		Add(fiber.MethodPost, "/files/*", validator.RestrictUnicode{Fields: []string{"name"}})
+
+	app.Use(registry.Handler(app))
+	handler := func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	}
+	app.Post("/login", handler)
+	app.Post("/users/:id", handler)
+	app.Post("/files/*", handler)
+	app.Post("/upload", handler)
+
+	if err := registry.Validate(app); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var routes []string
+	for _, route := range registry.Routes() {
+		routes = append(routes, route.Method+" "+route.Path+" "+strings.Join(route.Names(), ","))
+	}
+	expectedRoutes := "POST /login MaxBodySize,RestrictUnicode;POST /users/:id MaxBodySize,RestrictNumberOnly;POST /files/* MaxBodySize,RestrictUnicode"
+	if got := strings.Join(routes, ";"); got != expectedRoutes {
+		t.Errorf("Expected routes '%s', got '%s'", expectedRoutes, got)
+	}
+
+	testCases := []struct {
+		name           string
+		path           string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Route rules - valid request",
+			path:           "/login",
+			requestBody:    `{"username":"gopher","age":"abc"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Route rules - invalid request",
+			path:           "/login",
+			requestBody:    `{"username":"gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Unicode characters are not allowed in the 'username' field","code":"UNICODE_NOT_ALLOWED","field":"username","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:           "Route with parameters - rules of the route only",
+			path:           "/users/42",
+			requestBody:    `{"username":"gøpher","age":30}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Route with parameters - invalid request",
+			path:           "/users/42",
+			requestBody:    `{"age":"abc"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'age' field must contain only numbers","code":"NUMBER_ONLY","field":"age","rule":"RestrictNumberOnly","params":{"fields":["age"]}}`,
+		},
+		{
+			name:           "Route with a wildcard - nested path",
+			path:           "/files/docs/readme",
+			requestBody:    `{"name":"rëadme"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:           "Route with a wildcard - empty wildcard",
+			path:           "/files",
+			requestBody:    `{"name":"rëadme"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:           "Route with a wildcard - case-insensitive path",
+			path:           "/Files/Docs",
+			requestBody:    `{"name":"readme"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Registry configuration applies to every declared route",
+			path:           "/login",
+			requestBody:    `{"username":"` + strings.Repeat("a", maxBodySize) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   fmt.Sprintf(`{"error":"Request body must not exceed %d bytes","code":"BODY_TOO_LARGE","rule":"MaxBodySize","params":{"max":%d}}`, maxBodySize, maxBodySize),
+		},
+		{
+			name:           "Route without rules",
+			path:           "/upload",
+			requestBody:    `{"username":"gøpher","padding":"` + strings.Repeat("a", maxBodySize) + `"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+
+	validationCases := []struct {
+		name        string
+		declare     [][2]string
+		expectedErr error
+	}{
+		{
+			name:        "Unknown route",
+			declare:     [][2]string{{fiber.MethodPost, "/logout"}},
+			expectedErr: validator.ErrUnknownRoute,
+		},
+		{
+			name:        "Unknown method",
+			declare:     [][2]string{{fiber.MethodPut, "/login"}},
+			expectedErr: validator.ErrUnknownRoute,
+		},
+		{
+			name:        "Duplicate route",
+			declare:     [][2]string{{fiber.MethodPost, "/login"}, {fiber.MethodPost, "/Login/"}},
+			expectedErr: validator.ErrOverlappingRoutes,
+		},
+		{
+			name:        "Overlapping route patterns",
+			declare:     [][2]string{{fiber.MethodPost, "/users/:id"}, {fiber.MethodPost, "/users/me"}},
+			expectedErr: validator.ErrOverlappingRoutes,
+		},
+		{
+			name:        "Route patterns with different parameter names",
+			declare:     [][2]string{{fiber.MethodPost, "/users/:id"}, {fiber.MethodPost, "/users/:name"}},
+			expectedErr: validator.ErrOverlappingRoutes,
+		},
+	}
+
+	for _, tc := range validationCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/login", handler)
+			app.Post("/users/me", handler)
+			app.Post("/users/:id", handler)
+
+			registry := validator.NewRegistry()
+			for _, route := range tc.declare {
+				registry.Add(route[0], route[1])
+			}
+			if err := registry.Validate(app); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+
+	t.Run("Invalid registry fails at startup", func(t *testing.T) {
+		app := fiber.New(fiber.Config{DisableStartupMessage: true})
+		registry := validator.NewRegistry().Add(fiber.MethodPost, "/logout")
+		app.Use(registry.Handler(app))
+		app.Post("/login", handler)
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer ln.Close()
+
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, validator.ErrUnknownRoute) {
+				t.Errorf("Expected a panic with %v, got %v", validator.ErrUnknownRoute, err)
+			}
+		}()
+		_ = app.Listener(ln)
+		t.Error("Expected the app to fail at startup")
+	})
+}
+
+// restrictPrefix is a custom rule used to test RegisterRule.
+type restrictPrefix struct {
+	Field  string
+	Prefix string
+}
+
+func (r restrictPrefix) Restrict(c *fiber.Ctx) error {
+	var body map[string]interface{}
+	if err := c.BodyParser(&body); err != nil {
+		return err
+	}
+	if value, ok := body[r.Field].(string); ok && !strings.HasPrefix(value, r.Prefix) {
+		return &validator.Error{Status: fiber.StatusBadRequest, Message: "Missing prefix in " + r.Field}
+	}
+	return nil
+}
+
+func TestLoadConfig(t *testing.T) {
+	validator.RegisterRule("RestrictPrefix", func(decode func(v interface{}) error) (validator.Restrictor, error) {
+		var spec struct {
+			Field  string `yaml:"field"`
+			Prefix string `yaml:"prefix"`
+		}
+		if err := decode(&spec); err != nil {
+			return nil, err
+		}
+		if spec.Prefix == "" {
+			return nil, errors.New("prefix must not be empty")
+		}
+		return restrictPrefix{Field: spec.Field, Prefix: spec.Prefix}, nil
+	})
+
+	yamlConfig := `
+maxBodySize: 1024
+jsonLimits:
+  maxDepth: 4
+rules:
+  - type: RestrictUnicode
+    fields: [name]
+  - type: RestrictStringLength
+    fields: [name]
+    maxLength: 8
+    status: 422
+    message: "{field} is too long"
+  - type: RestrictNumberOnly
+    fields: [age]
+    max: 150
+  - type: RestrictPrefix
+    field: id
+    prefix: "usr_"
+`
+
+	jsonConfig := "{\n\t\"maxBodySize\": 1024,\n\t\"jsonLimits\": {\"maxDepth\": 4},\n\t\"rules\": [\n" +
+		"\t\t{\"type\": \"RestrictUnicode\", \"fields\": [\"name\"]},\n" +
+		"\t\t{\"type\": \"RestrictStringLength\", \"fields\": [\"name\"], \"maxLength\": 8, \"status\": 422, \"message\": \"{field} is too long\"},\n" +
+		"\t\t{\"type\": \"RestrictNumberOnly\", \"fields\": [\"age\"], \"max\": 150},\n" +
+		"\t\t{\"type\": \"RestrictPrefix\", \"field\": \"id\", \"prefix\": \"usr_\"}\n" +
+		"\t]\n}"
+
+	for format, config := range map[string]string{"YAML": yamlConfig, "JSON": jsonConfig} {
+		cfg, err := validator.LoadConfig(strings.NewReader(config))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+
+		app := fiber.New()
+		app.Use(validator.New(cfg))
+		app.Post("/", func(c *fiber.Ctx) error {
+			return c.SendString("OK")
+		})
+
+		testCases := []struct {
+			name           string
+			requestBody    string
+			expectedStatus int
+			expectedBody   string
+		}{
+			{
+				name:           "Valid request",
+				requestBody:    `{"id":"usr_1","name":"Gopher","age":30}`,
+				expectedStatus: http.StatusOK,
+				expectedBody:   "OK",
+			},
+			{
+				name:           "Unicode rule",
+				requestBody:    `{"id":"usr_1","name":"Gøpher"}`,
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   `{"error":"Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode"}`,
+			},
+			{
+				name:           "String length rule with overrides",
+				requestBody:    `{"id":"usr_1","name":"GopherGopher"}`,
+				expectedStatus: http.StatusUnprocessableEntity,
+				expectedBody:   `{"error":"name is too long","code":"MAX_LENGTH_EXCEEDED","field":"name","rule":"RestrictStringLength","params":{"max":8}}`,
+			},
+			{
+				name:           "Number rule",
+				requestBody:    `{"id":"usr_1","age":200}`,
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   `{"error":"The 'age' field must not exceed 150","code":"MAX_VALUE_EXCEEDED","field":"age","rule":"RestrictNumberOnly","params":{"max":150}}`,
+			},
+			{
+				name:           "Custom rule",
+				requestBody:    `{"id":"1"}`,
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   `{"error":"Missing prefix in id","rule":"restrictPrefix"}`,
+			},
+			{
+				name:           "JSON limits",
+				requestBody:    `{"a":{"b":{"c":{"d":{}}}}}`,
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   `{"error":"JSON request body must not exceed a nesting depth of 4","code":"JSON_MAX_DEPTH_EXCEEDED","rule":"JSONLimits","params":{"max":4}}`,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(format+" - "+tc.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+				req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+				resp, err := app.Test(req)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode != tc.expectedStatus {
+					t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+				}
+
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					t.Fatalf("Unexpected error reading response body: %v", err)
+				}
+
+				if strings.TrimSpace(string(body)) != tc.expectedBody {
+					t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+				}
+			})
+		}
+	}
+
+	errorCases := []struct {
+		name          string
+		config        string
+		expectedError string
+	}{
+		{
+			name:          "Unknown rule type",
+			config:        "rules:\n  - type: RestrictEmoji\n    fields: [name]\n",
+			expectedError: `validator: line 2, column 11: unknown rule type "RestrictEmoji"`,
+		},
+		{
+			name:          "Unknown parameter",
+			config:        "rules:\n  - type: RestrictStringLength\n    fields: [name]\n    maxLenght: 8\n",
+			expectedError: `validator: line 4, column 5: unknown field "maxLenght"`,
+		},
+		{
+			name:          "Invalid parameter value",
+			config:        "rules:\n  - type: RestrictNumberOnly\n    fields: [age]\n    max: many\n",
+			expectedError: `validator: line 4, column 10: invalid int value "many"`,
+		},
+		{
+			name:          "Invalid status",
+			config:        "rules:\n  - type: RestrictUnicode\n    fields: [name]\n    status: 1000\n",
+			expectedError: `validator: line 2, column 5: RestrictUnicode: invalid status 1000`,
+		},
+		{
+			name:          "Invalid custom rule",
+			config:        `{"rules": [{"type": "RestrictPrefix", "field": "id"}]}`,
+			expectedError: `validator: line 1, column 12: RestrictPrefix: prefix must not be empty`,
+		},
+		{
+			name:          "Negative JSON limit",
+			config:        "jsonLimits:\n  maxDepth: 8\n  maxKeys: -1\n",
+			expectedError: `validator: line 3, column 12: maxKeys must not be negative`,
+		},
+		{
+			name:          "Negative JSON string length limit in JSON",
+			config:        `{"jsonLimits": {"maxStringLength": -5}}`,
+			expectedError: `validator: line 1, column 36: maxStringLength must not be negative`,
+		},
+		{
+			name:          "Unknown option",
+			config:        "maxBodySize: 1024\nreportonly: true\n",
+			expectedError: `validator: line 2, column 1: unknown field "reportonly"`,
+		},
+		{
+			name:          "Invalid JSON type",
+			config:        "rules:\n  - type: RestrictType\n    fields: [age]\n    jsonType: int\n",
+			expectedError: `validator: line 2, column 5: RestrictType: invalid jsonType "int"`,
+		},
+		{
+			name:          "Inverted item limits",
+			config:        "rules:\n  - type: RestrictArray\n    fields: [tags]\n    minItems: 5\n    maxItems: 1\n",
+			expectedError: `validator: line 2, column 5: RestrictArray: minItems must not exceed maxItems`,
+		},
+		{
+			name:          "Invalid element rule",
+			config:        "rules:\n  - type: RestrictArray\n    fields: [tags]\n    each:\n      - type: RestrictStringLength\n        maxLength: -1\n",
+			expectedError: `validator: line 5, column 9: RestrictStringLength: maxLength must not be negative`,
+		},
+		{
+			name:          "Invalid file signature",
+			config:        "rules:\n  - type: RestrictFiles\n    signatures:\n      - contentType: image/heic\n        offset: 4\n        magic: ftypheic\n",
+			expectedError: `validator: line 2, column 5: RestrictFiles: invalid magic "ftypheic" of signature "image/heic"`,
+		},
+		{
+			name:          "Negative file signature offset",
+			config:        "rules:\n  - type: RestrictFiles\n    signatures:\n      - contentType: image/heic\n        offset: -4\n        magic: 6674797068656963\n",
+			expectedError: `validator: line 2, column 5: RestrictFiles: offset of signature "image/heic" must not be negative`,
+		},
+		{
+			name:          "Invalid allowed content type",
+			config:        "allowedContentTypes: [application/json, json]\n",
+			expectedError: `validator: line 1, column 41: invalid media type "json"`,
+		},
+		{
+			name:          "Invalid allowed content type of a rule",
+			config:        "rules:\n  - type: RestrictUnicode\n    fields: [name]\n    allowedContentTypes: [text/plain; charset=utf-8]\n",
+			expectedError: `validator: line 2, column 5: RestrictUnicode: invalid media type "text/plain; charset=utf-8"`,
+		},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := validator.LoadConfig(strings.NewReader(tc.config))
+			var configErr *validator.ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Expected *validator.ConfigError, got %v", err)
+			}
+			if err.Error() != tc.expectedError {
+				t.Errorf("Expected error '%s', got '%s'", tc.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+// blockingRule is a rule that blocks requests with the X-Block header until it is released.
+type blockingRule struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r blockingRule) Restrict(c *fiber.Ctx) error {
+	if c.Get("X-Block") != "" {
+		r.started <- struct{}{}
+		<-r.release
+	}
+	return nil
+}
+
+func TestReloader(t *testing.T) {
+	var mu sync.Mutex
+	var reloadErrors []error
+	reloader := &validator.Reloader{
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reloadErrors = append(reloadErrors, err)
+		},
+	}
+	errorCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(reloadErrors)
+	}
+
+	blocking := blockingRule{started: make(chan struct{}), release: make(chan struct{})}
+
+	app := fiber.New()
+	app.Use(validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			blocking,
+			validator.RestrictUnicode{
+				Fields: []string{"name"},
+			},
+		},
+		Reloader: reloader,
+	}))
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	send := func(requestBody string, block bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(requestBody))
+		req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+		if block {
+			req.Header.Set("X-Block", "1")
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := send(`{"name":"Gøpher"}`, false); status != http.StatusBadRequest {
+		t.Fatalf("Initial rules: expected status %d, got %d", http.StatusBadRequest, status)
+	}
+
+	// A request that is being validated keeps the rules it started with.
+	inFlight := make(chan int)
+	go func() {
+		inFlight <- send(`{"name":"Gøpher"}`, true)
+	}()
+	<-blocking.started
+
+	err := reloader.Reload(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictNumberOnly{
+				Fields: []string{"age"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	close(blocking.release)
+	if status := <-inFlight; status != http.StatusBadRequest {
+		t.Errorf("In-flight request: expected status %d, got %d", http.StatusBadRequest, status)
+	}
+	if status := send(`{"name":"Gøpher"}`, false); status != http.StatusOK {
+		t.Errorf("Reloaded rules: expected status %d, got %d", http.StatusOK, status)
+	}
+	if status := send(`{"age":"abc"}`, false); status != http.StatusBadRequest {
+		t.Errorf("Reloaded rules: expected status %d, got %d", http.StatusBadRequest, status)
+	}
+
+	// Failed reloads keep the previous rules.
+	if err := reloader.Reload(validator.Config{EnforcePercentage: 200}); err == nil {
+		t.Error("Expected an error for an invalid configuration")
+	}
+	if err := reloader.ReloadFrom(strings.NewReader("rules:\n  - type: RestrictEmoji\n")); err == nil {
+		t.Error("Expected an error for an invalid configuration file")
+	}
+	if n := errorCount(); n != 2 {
+		t.Errorf("Expected 2 reload errors, got %d", n)
+	}
+	if status := send(`{"age":"abc"}`, false); status != http.StatusBadRequest {
+		t.Errorf("Failed reload: expected status %d, got %d", http.StatusBadRequest, status)
+	}
+
+	// The watcher reloads the file when it changes.
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n  - type: RestrictUnicode\n    fields: [name]\n"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		reloader.Watch(ctx, path, 10*time.Millisecond)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for %s", what)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	waitFor("the watched file to be loaded", func() bool {
+		return send(`{"name":"Gøpher"}`, false) == http.StatusBadRequest
+	})
+	if status := send(`{"age":"abc"}`, false); status != http.StatusOK {
+		t.Errorf("Watched rules: expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if err := os.WriteFile(path, []byte("rules:\n  - type: RestrictUnicode\n    fields: name\n"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitFor("the invalid file to be reported", func() bool {
+		return errorCount() == 3
+	})
+	if status := send(`{"name":"Gøpher"}`, false); status != http.StatusBadRequest {
+		t.Errorf("Failed watched reload: expected status %d, got %d", http.StatusBadRequest, status)
+	}
+
+	t.Run("Reuse by another middleware panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected New to panic")
+			}
+		}()
+		validator.New(validator.Config{Reloader: reloader})
+	})
+
+	t.Run("Watch with a non-positive interval", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		(&validator.Reloader{}).Watch(ctx, path, 0)
+	})
+}
+
+func TestRestrictOpenAPI(t *testing.T) {
+	spec := `
+openapi: 3.1.0
+info:
+  title: Users
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      parameters:
+        - $ref: '#/components/parameters/RequestID'
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+          application/x-www-form-urlencoded:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        201:
+          description: Created
+    get:
+      parameters:
+        - name: Authorization
+          in: header
+          required: true
+          schema:
+            type: string
+            pattern: '^Bearer '
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 100
+        - name: tag
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+            maxItems: 2
+      responses:
+        200:
+          description: OK
+  /users/me:
+    get:
+      responses:
+        200:
+          description: OK
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+            pattern: '^[0-9]+$'
+      responses:
+        200:
+          description: OK
+components:
+  parameters:
+    RequestID:
+      name: X-Request-ID
+      in: header
+      required: true
+      schema:
+        type: string
+  schemas:
+    User:
+      type: object
+      required: [name, role]
+      additionalProperties: false
+      properties:
+        name:
+          type: string
+          minLength: 1
+          maxLength: 10
+        role:
+          enum: [admin, member]
+        age:
+          type: [integer, "null"]
+          minimum: 0
+        address:
+          $ref: '#/components/schemas/Address'
+        manager:
+          $ref: '#/components/schemas/User'
+    Address:
+      type: object
+      properties:
+        zip:
+          type: string
+          pattern: '^[0-9]{5}$'
+`
+
+	restrictor, err := validator.LoadOpenAPI(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	restrictor.BasePath = "/api"
+
+	app := fiber.New()
+	app.Use(validator.New(validator.Config{
+		Rules: []validator.Restrictor{restrictor},
+	}))
+	handler := func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	}
+	app.Post("/api/users", handler)
+	app.Get("/api/users", handler)
+	app.Get("/api/users/:id", handler)
+	app.Delete("/api/users/:id", handler)
+	app.Get("/api/orders", handler)
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		requestID      string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid JSON body",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestID:      "1",
+			requestBody:    `{"name":"Gopher","role":"admin","age":null,"address":{"zip":"12345"},"manager":{"name":"Gaby","role":"admin"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Valid form body",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationForm,
+			requestID:      "1",
+			requestBody:    "name=Gopher&role=member&age=30",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Invalid form value type",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationForm,
+			requestID:      "1",
+			requestBody:    "name=Gopher&role=member&age=old",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The '/body/age' field must be of type integer or null",
+		},
+		{
+			name:           "Missing required header",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"Gopher","role":"admin"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The '/header/X-Request-ID' field is required","code":"SCHEMA_VIOLATION","field":"/header/X-Request-ID","rule":"RestrictOpenAPI","params":{"keyword":"required"}}`,
+		},
+		{
+			name:           "Missing required body",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestID:      "1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The '/body' field is required","code":"SCHEMA_VIOLATION","field":"/body","rule":"RestrictOpenAPI","params":{"keyword":"required"}}`,
+		},
+		{
+			name:           "Missing required property",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestID:      "1",
+			requestBody:    `{"name":"Gopher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The '/body/role' field is required","code":"SCHEMA_VIOLATION","field":"/body/role","rule":"RestrictOpenAPI","params":{"keyword":"required"}}`,
+		},
+		{
+			name:           "Property too long",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestID:      "1",
+			requestBody:    `{"name":"GopherGopher","role":"admin"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The '/body/name' field must not exceed 10 characters","code":"SCHEMA_VIOLATION","field":"/body/name","rule":"RestrictOpenAPI","params":{"keyword":"maxLength","limit":10}}`,
+		},
+		{
+			name:           "Nested pattern through references",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestID:      "1",
+			requestBody:    `{"name":"Gopher","role":"admin","manager":{"name":"Gaby","role":"admin","address":{"zip":"1234"}}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The '/body/manager/address/zip' field must match the pattern '^[0-9]{5}$'","code":"SCHEMA_VIOLATION","field":"/body/manager/address/zip","rule":"RestrictOpenAPI","params":{"keyword":"pattern","limit":"^[0-9]{5}$"}}`,
+		},
+		{
+			name:           "Enum",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestID:      "1",
+			requestBody:    `{"name":"Gopher","role":"root"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The '/body/role' field must be one of the allowed values","code":"SCHEMA_VIOLATION","field":"/body/role","rule":"RestrictOpenAPI","params":{"keyword":"enum"}}`,
+		},
+		{
+			name:           "Additional property",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestID:      "1",
+			requestBody:    `{"name":"Gopher","role":"admin","admin":true}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The '/body/admin' field is not allowed","code":"SCHEMA_VIOLATION","field":"/body/admin","rule":"RestrictOpenAPI","params":{"keyword":"additionalProperties"}}`,
+		},
+		{
+			name:           "Unsupported media type",
+			method:         http.MethodPost,
+			path:           "/api/users",
+			contentType:    fiber.MIMEApplicationXML,
+			requestID:      "1",
+			requestBody:    `<user><name>Gopher</name></user>`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `<xmlError><error>Unsupported media type &#39;application/xml&#39;</error><code>UNSUPPORTED_MEDIA_TYPE</code><rule>RestrictOpenAPI</rule><params><param name="mediaType">application/xml</param></params></xmlError>`,
+		},
+		{
+			name:           "Valid query parameters",
+			method:         http.MethodGet,
+			path:           "/api/users?limit=10&tag=a&tag=b",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Authorization header parameter is ignored",
+			method:         http.MethodGet,
+			path:           "/api/users?limit=10",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Query parameter out of range",
+			method:         http.MethodGet,
+			path:           "/api/users?limit=1000",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The '/query/limit' field must not exceed 100",
+		},
+		{
+			name:           "Query parameter array too long",
+			method:         http.MethodGet,
+			path:           "/api/users?tag=a&tag=b&tag=c",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The '/query/tag' field must not contain more than 2 items",
+		},
+		{
+			name:           "Concrete path before templated path",
+			method:         http.MethodGet,
+			path:           "/api/users/me",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Invalid path parameter",
+			method:         http.MethodGet,
+			path:           "/api/users/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The '/path/id' field must match the pattern '^[0-9]+$'",
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodDelete,
+			path:           "/api/users/42",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "Method DELETE is not allowed for /users/42",
+		},
+		{
+			name:           "Unknown operation",
+			method:         http.MethodGet,
+			path:           "/api/orders",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "No operation is defined for GET /orders",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.requestBody))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			if tc.requestID != "" {
+				req.Header.Set("X-Request-ID", tc.requestID)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+
+	invalidSpecs := []struct {
+		name string
+		spec string
+	}{
+		{
+			name: "Remote reference",
+			spec: `{"openapi": "3.1.0", "paths": {"/users": {"$ref": "https://example.com/users.yaml"}}}`,
+		},
+		{
+			name: "Missing reference",
+			spec: `{"openapi": "3.1.0", "paths": {"/users": {"post": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}}}}}`,
+		},
+		{
+			name: "Invalid pattern",
+			spec: `{"openapi": "3.1.0", "paths": {"/users/{id}": {"get": {"parameters": [{"name": "id", "in": "path", "schema": {"pattern": "("}}]}}}}`,
+		},
+		{
+			name: "Swagger 2.0",
+			spec: `{"swagger": "2.0", "paths": {}}`,
+		},
+	}
+
+	for _, tc := range invalidSpecs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := validator.LoadOpenAPI(strings.NewReader(tc.spec)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	t.Run("Localized messages", func(t *testing.T) {
+		l := validator.NewLocalizer()
+		for _, tc := range []struct {
+			err      *validator.Error
+			expected string
+		}{
+			{
+				err:      &validator.Error{Code: validator.CodeSchemaViolation, Field: "/body/age", Message: "The '/body/age' field must not exceed 150"},
+				expected: "Das Feld '/body/age' entspricht nicht der API-Spezifikation",
+			},
+			{
+				err:      &validator.Error{Code: validator.CodeOperationNotFound, Message: "No operation is defined for GET /api/orders"},
+				expected: "Für diese Anfrage ist keine Operation definiert",
+			},
+			{
+				err:      &validator.Error{Code: validator.CodeMethodNotAllowed, Message: "Method DELETE is not allowed for /users/1"},
+				expected: "Die Methode der Anfrage ist für diesen Pfad nicht erlaubt",
+			},
+		} {
+			if got := l.Localize(tc.err, language.German); got != tc.expected {
+				t.Errorf("Expected message '%s', got '%s'", tc.expected, got)
+			}
+		}
+	})
+}
+
+func TestOpenAPISchema(t *testing.T) {
+	maxLength := 32
+	max := 100
+	maxDigits := 3
+
+	cfg := validator.Config{
+		MaxBodySize: 1024,
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"name", "code"}},
+			validator.RestrictStringLength{Fields: []string{"name"}, MaxLength: &maxLength},
+			validator.RestrictNumberOnly{Fields: []string{"age", "code"}, Max: &max, MaxDigits: &maxDigits},
+			validator.RestrictStringLength{Fields: []string{"code"}, MaxLength: &maxLength},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		fragment func() ([]byte, error)
+		expected string
+	}{
+		{
+			name:     "Config as JSON",
+			fragment: validator.OpenAPISchema(cfg).JSON,
+			expected: `{
+  "properties": {
+    "age": {
+      "maxLength": 3,
+      "maximum": 100,
+      "pattern": "^[0-9]*$"
+    },
+    "code": {
+      "allOf": [
+        {
+          "pattern": "^[0-9]*$"
+        }
+      ],
+      "maxLength": 3,
+      "maximum": 100,
+      "pattern": "^[\\x00-\\x7F]*$"
+    },
+    "name": {
+      "maxLength": 32,
+      "pattern": "^[\\x00-\\x7F]*$"
+    }
+  },
+  "type": "object"
+}`,
+		},
+		{
+			name:     "Config without schema constraints",
+			fragment: validator.OpenAPISchema(validator.Config{MaxBodySize: 1024}).YAML,
+			expected: "type: object\n",
+		},
+		{
+			name: "Registry as YAML",
+			fragment: validator.NewRegistry(validator.Config{
+				Rules: []validator.Restrictor{validator.RestrictUnicode{Fields: []string{"name"}}},
+			}).
+				Add(fiber.MethodPost, "/users/:id<int>", validator.RestrictStringLength{Fields: []string{"name"}, MaxLength: &maxLength}).
+				Add(fiber.MethodPut, "/users/:id<int>").
+				OpenAPIPaths().YAML,
+			expected: `/users/{id}:
+    post:
+        requestBody:
+            content:
+                application/json:
+                    schema:
+                        properties:
+                            name:
+                                maxLength: 32
+                                pattern: ^[\x00-\x7F]*$
+                        type: object
+    put:
+        requestBody:
+            content:
+                application/json:
+                    schema:
+                        properties:
+                            name:
+                                pattern: ^[\x00-\x7F]*$
+                        type: object
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.fragment()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(data) != tc.expected {
+				t.Errorf("Expected fragment:\n%s\ngot:\n%s", tc.expected, data)
+			}
+		})
+	}
+}
+
+func TestDocumentBodies(t *testing.T) {
+	maxLength := 8
+	max := 150
+
+	app := fiber.New()
+	app.Post("/", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"username"}},
+			validator.RestrictStringLength{Fields: []string{"username"}, MaxLength: &maxLength},
+			validator.RestrictNumberOnly{Fields: []string{"age"}, Max: &max},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+	app.Post("/bind", validator.Bind[bindLogin](), func(c *fiber.Ctx) error {
+		login := validator.Body[bindLogin](c)
+		return c.SendString(fmt.Sprintf("%s:%d", login.Username, login.Age))
+	})
+
+	// Every level doubles the size of the document when its aliases are expanded.
+	billionLaughs := "a0: &a0 [x, x]\n"
+	for i := 1; i <= 20; i++ {
+		billionLaughs += fmt.Sprintf("a%d: &a%d [*a%d, *a%d]\n", i, i, i-1, i-1)
+	}
+
+	mustMsgPack := func(v interface{}) []byte {
+		data, err := msgpack.Marshal(v)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return data
+	}
+	mustCBOR := func(v interface{}) []byte {
+		data, err := cbor.Marshal(v)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return data
+	}
+
+	testCases := []struct {
+		name           string
+		path           string
+		contentType    string
+		requestBody    []byte
+		expectedStatus int
+		expectedBody   string
+		expectedError  map[string]interface{}
+	}{
+		{
+			name:           "Valid MessagePack body",
+			path:           "/",
+			contentType:    validator.MIMEApplicationMsgPack,
+			requestBody:    mustMsgPack(map[string]interface{}{"username": "gopher", "age": 30}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "MessagePack body with Unicode characters",
+			path:           "/",
+			contentType:    "application/x-msgpack",
+			requestBody:    mustMsgPack(map[string]interface{}{"username": "gøpher", "age": 30}),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error": "Unicode characters are not allowed in the 'username' field",
+				"code":  "UNICODE_NOT_ALLOWED",
+				"field": "username",
+				"rule":  "RestrictUnicode",
+			},
+		},
+		{
+			name:           "MessagePack body with an unsigned integer exceeding the maximum",
+			path:           "/",
+			contentType:    validator.MIMEApplicationMsgPack,
+			requestBody:    mustMsgPack(map[string]interface{}{"username": "gopher", "age": uint8(200)}),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error":  "The 'age' field must not exceed 150",
+				"code":   "MAX_VALUE_EXCEEDED",
+				"field":  "age",
+				"rule":   "RestrictNumberOnly",
+				"params": map[string]interface{}{"max": uint8(150)},
+			},
+		},
+		{
+			name:           "Invalid MessagePack body",
+			path:           "/",
+			contentType:    validator.MIMEApplicationMsgPack,
+			requestBody:    []byte{0xc1},
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error": "Invalid MessagePack request body",
+				"code":  "INVALID_MSGPACK_BODY",
+				"rule":  "RestrictUnicode",
+			},
+		},
+		{
+			name:           "Valid CBOR body",
+			path:           "/",
+			contentType:    validator.MIMEApplicationCBOR,
+			requestBody:    mustCBOR(map[string]interface{}{"username": "gopher", "age": "42"}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "CBOR body with a field that is too long",
+			path:           "/",
+			contentType:    validator.MIMEApplicationCBOR,
+			requestBody:    mustCBOR(map[string]interface{}{"username": "gopher-gopher", "age": 30}),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error":  "The 'username' field must not exceed 8 characters",
+				"code":   "MAX_LENGTH_EXCEEDED",
+				"field":  "username",
+				"rule":   "RestrictStringLength",
+				"params": map[interface{}]interface{}{"max": uint64(8)},
+			},
+		},
+		{
+			name:           "CBOR body that is not an object",
+			path:           "/",
+			contentType:    validator.MIMEApplicationCBOR,
+			requestBody:    mustCBOR([]string{"gopher"}),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error": "Invalid CBOR request body",
+				"code":  "INVALID_CBOR_BODY",
+				"rule":  "RestrictUnicode",
+			},
+		},
+		{
+			name:           "CBOR body with keys of the same string form",
+			path:           "/",
+			contentType:    validator.MIMEApplicationCBOR,
+			requestBody:    mustCBOR(map[interface{}]interface{}{1: "gøpher", "1": "gopher", "username": "gopher"}),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error": "Invalid CBOR request body",
+				"code":  "INVALID_CBOR_BODY",
+				"rule":  "RestrictUnicode",
+			},
+		},
+		{
+			name:           "Valid YAML body with an alias",
+			path:           "/",
+			contentType:    validator.MIMEApplicationYAML,
+			requestBody:    []byte("defaults: &defaults\n  role: member\nuser: *defaults\nusername: gopher\nage: 30\n"),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "YAML body with Unicode characters",
+			path:           "/",
+			contentType:    "application/x-yaml",
+			requestBody:    []byte("username: gøpher\n"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error": "Unicode characters are not allowed in the 'username' field",
+				"code":  "UNICODE_NOT_ALLOWED",
+				"field": "username",
+				"rule":  "RestrictUnicode",
+			},
+		},
+		{
+			name:           "YAML body with aliases that expand too much",
+			path:           "/",
+			contentType:    validator.MIMEApplicationYAML,
+			requestBody:    []byte(billionLaughs),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error": "Invalid YAML request body",
+				"code":  "INVALID_YAML_BODY",
+				"rule":  "RestrictUnicode",
+			},
+		},
+		{
+			name:           "YAML body with several documents",
+			path:           "/",
+			contentType:    validator.MIMEApplicationYAML,
+			requestBody:    []byte("username: gopher\n---\nusername: gøpher\n"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error": "Invalid YAML request body",
+				"code":  "INVALID_YAML_BODY",
+				"rule":  "RestrictUnicode",
+			},
+		},
+		{
+			name:           "Valid TOML body",
+			path:           "/",
+			contentType:    validator.MIMEApplicationTOML,
+			requestBody:    []byte("username = \"gopher\"\nage = 30\n"),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "TOML body with a number exceeding the maximum",
+			path:           "/",
+			contentType:    validator.MIMEApplicationTOML,
+			requestBody:    []byte("username = \"gopher\"\nage = 200\n"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError: map[string]interface{}{
+				"error":  "The 'age' field must not exceed 150",
+				"code":   "MAX_VALUE_EXCEEDED",
+				"field":  "age",
+				"rule":   "RestrictNumberOnly",
+				"params": map[string]interface{}{"max": int64(150)},
+			},
+		},
+		{
+			name:           "Bind MessagePack body",
+			path:           "/bind",
+			contentType:    validator.MIMEApplicationMsgPack,
+			requestBody:    mustMsgPack(map[string]interface{}{"username": "gopher", "age": 30}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "gopher:30",
+		},
+		{
+			name:           "Bind CBOR body",
+			path:           "/bind",
+			contentType:    validator.MIMEApplicationCBOR,
+			requestBody:    mustCBOR(map[string]interface{}{"username": "gopher", "age": 30}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "gopher:30",
+		},
+		{
+			name:           "Bind YAML body",
+			path:           "/bind",
+			contentType:    validator.MIMEApplicationYAML,
+			requestBody:    []byte("username: gopher\nage: 30\n"),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "gopher:30",
+		},
+		{
+			name:           "Bind TOML body",
+			path:           "/bind",
+			contentType:    validator.MIMEApplicationTOML,
+			requestBody:    []byte("username = \"gopher\"\nage = 30\n"),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "gopher:30",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if tc.expectedError == nil {
+				if string(body) != tc.expectedBody {
+					t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+				}
+				return
+			}
+
+			var got map[string]interface{}
+			switch contentType := resp.Header.Get("Content-Type"); contentType {
+			case validator.MIMEApplicationCBOR:
+				err = cbor.Unmarshal(body, &got)
+			case validator.MIMEApplicationMsgPack:
+				err = msgpack.Unmarshal(body, &got)
+			case validator.MIMEApplicationYAML:
+				err = yaml.Unmarshal(body, &got)
+			case validator.MIMEApplicationTOML:
+				err = toml.Unmarshal(body, &got)
+			default:
+				t.Fatalf("Unexpected content type '%s'", contentType)
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error decoding response body: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expectedError) {
+				t.Errorf("Expected error %#v, got %#v", tc.expectedError, got)
+			}
+		})
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	maxLength := 6
+	rules := []validator.Restrictor{
+		validator.RestrictUnicode{Fields: []string{"name"}},
+		validator.RestrictStringLength{Fields: []string{"name"}, MaxLength: &maxLength},
+	}
+	handler := func(c *fiber.Ctx) error {
+		// The request body is restored after its lines were validated.
+		return c.Send(c.Body())
+	}
+
+	app := fiber.New()
+	app.Post("/first", validator.New(validator.Config{
+		Rules:       rules,
+		MaxBodySize: 128,
+	}), handler)
+	app.Post("/collect", validator.New(validator.Config{
+		Rules:           rules,
+		NDJSONMaxErrors: 2,
+	}), handler)
+
+	testCases := []struct {
+		name           string
+		path           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid lines",
+			path:           "/first",
+			contentType:    validator.MIMEApplicationNDJSON,
+			requestBody:    "{\"name\":\"gopher\"}\n\n{\"name\":\"gaby\"}\r\n",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{\"name\":\"gopher\"}\n\n{\"name\":\"gaby\"}\r\n",
+		},
+		{
+			name:           "Invalid line stops the validation",
+			path:           "/first",
+			contentType:    "application/jsonl",
+			requestBody:    "{\"name\":\"gopher\"}\n\n{\"name\":\"gøpher\"}\n{\"name\":\"gøpher\"}\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Line 3: Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode","params":{"line":3}}`,
+		},
+		{
+			name:           "Line that is not JSON",
+			path:           "/first",
+			contentType:    validator.MIMEApplicationNDJSON,
+			requestBody:    "{\"name\":\"gopher\"}\nname=gopher\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Line 2: Invalid JSON request body","code":"INVALID_JSON_BODY","rule":"RestrictUnicode","params":{"line":2}}`,
+		},
+		{
+			name:           "Body size applies to the whole request",
+			path:           "/first",
+			contentType:    validator.MIMEApplicationNDJSON,
+			requestBody:    strings.Repeat("{\"name\":\"gopher\"}\n", 10),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request body must not exceed 128 bytes","code":"BODY_TOO_LARGE","rule":"MaxBodySize","params":{"max":128}}`,
+		},
+		{
+			name:           "Invalid lines are collected",
+			path:           "/collect",
+			contentType:    validator.MIMEApplicationNDJSON,
+			requestBody:    "{\"name\":\"gopher-gopher\"}\n{\"name\":\"gopher\"}\n{\"name\":\"gopher-gopher\"}\n{\"name\":\"gopher-gopher\"}\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Line 1: The 'name' field must not exceed 6 characters","code":"MAX_LENGTH_EXCEEDED","field":"name","rule":"RestrictStringLength","params":{"errors":[{"code":"MAX_LENGTH_EXCEEDED","error":"The 'name' field must not exceed 6 characters","field":"name","line":1},{"code":"MAX_LENGTH_EXCEEDED","error":"The 'name' field must not exceed 6 characters","field":"name","line":3}],"line":1,"max":6}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+
+	t.Run("LineError", func(t *testing.T) {
+		var lineErr *validator.LineError
+		app := fiber.New()
+		app.Post("/", validator.New(validator.Config{
+			Rules: rules,
+			ErrorHandler: func(c *fiber.Ctx, err error) error {
+				errors.As(err, &lineErr)
+				return validator.DefaultErrorHandler(c, err)
+			},
+		}), handler)
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"name\":\"gopher\"}\n{\"name\":\"gøpher\"}\n"))
+		req.Header.Set("Content-Type", validator.MIMEApplicationNDJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if lineErr == nil || lineErr.Line != 2 || !errors.Is(lineErr, validator.ErrUnicodeNotAllowed) {
+			t.Errorf("Expected a LineError for line 2 wrapping ErrUnicodeNotAllowed, got %v", lineErr)
+		}
+	})
+
+	t.Run("Localized line prefix", func(t *testing.T) {
+		l := validator.NewLocalizer()
+		e := &validator.Error{Code: validator.CodeInvalidJSONBody, Message: "Line 2: Invalid JSON request body", Params: map[string]any{"line": 2}}
+		for tag, expected := range map[language.Tag]string{
+			language.English:    "Line 2: Invalid JSON request body",
+			language.Indonesian: "Baris 2: Body permintaan JSON tidak valid",
+			language.German:     "Zeile 2: Ungültiger JSON-Anfragetext",
+		} {
+			if got := l.Localize(e, tag); got != expected {
+				t.Errorf("Expected message '%s', got '%s'", expected, got)
+			}
+		}
+	})
+}
+
+func TestMediaTypes(t *testing.T) {
+	// Request bodies of this media type are lines of "key: value" pairs.
+	validator.RegisterMediaType("text/x-key-value", func(data []byte, v interface{}) error {
+		doc := make(map[string]interface{})
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			key, value, ok := strings.Cut(line, ": ")
+			if !ok {
+				return fmt.Errorf("invalid line %q", line)
+			}
+			doc[key] = value
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, v)
+	})
+
+	app := fiber.New()
+	app.Post("/", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"name"}},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+	app.Post("/bind", validator.Bind[bindLogin](), func(c *fiber.Ctx) error {
+		return c.SendString(validator.Body[bindLogin](c).Username)
+	})
+
+	jsonError := `{"error":"Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode"}`
+	xmlError := `<xmlError><error>Unicode characters are not allowed in the &#39;name&#39; field</error><code>UNICODE_NOT_ALLOWED</code><field>name</field><rule>RestrictUnicode</rule></xmlError>`
+
+	testCases := []struct {
+		name           string
+		path           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "JSON with a spaced charset parameter",
+			path:           "/",
+			contentType:    "application/json ; charset=utf-8",
+			requestBody:    `{"name":"gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   jsonError,
+		},
+		{
+			name:           "Uppercase JSON media type",
+			path:           "/",
+			contentType:    "Application/JSON",
+			requestBody:    `{"name":"gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   jsonError,
+		},
+		{
+			name:           "JSON:API media type",
+			path:           "/",
+			contentType:    "application/vnd.api+json",
+			requestBody:    `{"name":"gøpher"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   jsonError,
+		},
+		{
+			name:           "JSON merge patch",
+			path:           "/",
+			contentType:    "application/merge-patch+json",
+			requestBody:    `{"name":"gopher"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "SOAP XML",
+			path:           "/",
+			contentType:    "application/soap+xml; charset=utf-8",
+			requestBody:    `<request><name>gøpher</name></request>`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   xmlError,
+		},
+		{
+			name:           "Registered media type",
+			path:           "/",
+			contentType:    "text/x-key-value; charset=utf-8",
+			requestBody:    "name: gøpher\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   jsonError,
+		},
+		{
+			name:           "Invalid body of a registered media type",
+			path:           "/",
+			contentType:    "text/x-key-value",
+			requestBody:    "name=gopher\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid request body","code":"INVALID_BODY","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:           "Bind a vendor-specific JSON body",
+			path:           "/bind",
+			contentType:    "application/vnd.login+json; charset=utf-8",
+			requestBody:    `{"username":"gopher"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "gopher",
+		},
+		{
+			name:           "Bind a registered media type",
+			path:           "/bind",
+			contentType:    "text/x-key-value",
+			requestBody:    "username: gopher\n",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "gopher",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+
+	t.Run("Duplicate media type", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected RegisterMediaType to panic for a known media type")
+			}
+		}()
+		validator.RegisterMediaType("Application/JSON", json.Unmarshal)
+	})
+}
+
+func TestTranscode(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", validator.New(validator.Config{
+		Transcode: &validator.Transcode{AllowedCharsets: []string{"ISO-8859-1", "Shift_JIS", "UTF-16", "UTF-16LE"}},
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"name"}},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString(c.Get(fiber.HeaderContentType) + " " + string(c.Body()))
+	})
+
+	// utf16le encodes an ASCII string as UTF-16LE.
+	utf16le := func(s string) string {
+		var b strings.Builder
+		for i := 0; i < len(s); i++ {
+			b.WriteByte(s[i])
+			b.WriteByte(0)
+		}
+		return b.String()
+	}
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Latin-1 JSON with an ASCII value",
+			contentType:    "application/json; charset=ISO-8859-1",
+			requestBody:    `{"name":"gopher","bio":"caf` + "\xe9" + `"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `application/json; charset=utf-8 {"name":"gopher","bio":"café"}`,
+		},
+		{
+			name:           "Latin-1 JSON with a non-ASCII value",
+			contentType:    "application/json; charset=latin1",
+			requestBody:    `{"name":"caf` + "\xe9" + `"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode"}`,
+		},
+		{
+			name:           "Shift_JIS XML declaration",
+			contentType:    "application/xml",
+			requestBody:    `<?xml version="1.0" encoding="Shift_JIS"?><request><bio>` + "\x93\xfa\x96\x7b" + `</bio></request>`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `application/xml <?xml version="1.0" encoding="UTF-8"?><request><bio>日本</bio></request>`,
+		},
+		{
+			name:           "UTF-16LE JSON",
+			contentType:    "application/json; charset=UTF-16LE",
+			requestBody:    utf16le(`{"name":"gopher"}`),
+			expectedStatus: http.StatusOK,
+			expectedBody:   `application/json; charset=utf-8 {"name":"gopher"}`,
+		},
+		{
+			name:           "UTF-16 XML with a byte order mark",
+			contentType:    "application/xml",
+			requestBody:    "\xff\xfe" + utf16le(`<?xml version="1.0" encoding="UTF-16"?><request><bio>hi</bio></request>`),
+			expectedStatus: http.StatusOK,
+			expectedBody:   `application/xml <?xml version="1.0" encoding="UTF-8"?><request><bio>hi</bio></request>`,
+		},
+		{
+			name:           "UTF-16LE XML without a byte order mark",
+			contentType:    "text/xml",
+			requestBody:    utf16le(`<?xml version="1.0" encoding="UTF-16"?><request><bio>hi</bio></request>`),
+			expectedStatus: http.StatusOK,
+			expectedBody:   `text/xml <?xml version="1.0" encoding="UTF-8"?><request><bio>hi</bio></request>`,
+		},
+		{
+			name:           "Shift_JIS NDJSON with a backslash byte in a character",
+			contentType:    validator.MIMEApplicationNDJSON + "; charset=Shift_JIS",
+			requestBody:    `{"bio":"` + "\x83\x5c" + `"}` + "\n" + `{"name":"gopher"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   validator.MIMEApplicationNDJSON + `; charset=utf-8 {"bio":"ソ"}` + "\n" + `{"name":"gopher"}`,
+		},
+		{
+			name:           "UTF-16LE NDJSON with a non-ASCII value",
+			contentType:    validator.MIMEApplicationNDJSON + "; charset=UTF-16LE",
+			requestBody:    utf16le(`{"name":"gopher"}`+"\n"+`{"name":"caf`) + "\xe9\x00" + utf16le(`"}`),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Line 2: Unicode characters are not allowed in the 'name' field","code":"UNICODE_NOT_ALLOWED","field":"name","rule":"RestrictUnicode","params":{"line":2}}`,
+		},
+		{
+			name:           "UTF-8 JSON",
+			contentType:    "application/json; charset=utf-8",
+			requestBody:    `{"name":"gopher"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `application/json; charset=utf-8 {"name":"gopher"}`,
+		},
+		{
+			name:           "Unknown charset",
+			contentType:    "application/json; charset=x-bogus",
+			requestBody:    `{"name":"gopher"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `{"error":"Unsupported charset 'x-bogus'","code":"UNSUPPORTED_CHARSET","rule":"Transcode","params":{"charset":"x-bogus"}}`,
+		},
+		{
+			name:           "Disallowed charset",
+			contentType:    "application/json; charset=windows-1252",
+			requestBody:    `{"name":"gopher"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `{"error":"Unsupported charset 'windows-1252'","code":"UNSUPPORTED_CHARSET","rule":"Transcode","params":{"charset":"windows-1252"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+
+	t.Run("Registry", func(t *testing.T) {
+		app := fiber.New()
+		registry := validator.NewRegistry(validator.Config{
+			Transcode: &validator.Transcode{},
+		}).Add(fiber.MethodPost, "/users", validator.RestrictUnicode{Fields: []string{"name"}})
+		app.Use(registry.Handler(app))
+		app.Post("/users", func(c *fiber.Ctx) error {
+			// The charset of a transcoded body is UTF-8, so only the evaluations reveal a second transcoding.
+			transcoded := 0
+			for _, evaluation := range validator.ResultFrom(c).Rules {
+				if evaluation.Rule == "Transcode" {
+					transcoded++
+				}
+			}
+			return c.SendString(fmt.Sprintf("%d %s", transcoded, c.Body()))
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"gopher","bio":"caf`+"\xe9"+`"}`))
+		req.Header.Set("Content-Type", "application/json; charset=ISO-8859-1")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error reading response body: %v", err)
+		}
+
+		expected := `1 {"name":"gopher","bio":"café"}`
+		if resp.StatusCode != http.StatusOK || string(body) != expected {
+			t.Errorf("Expected status %d and body '%s', got %d and '%s'", http.StatusOK, expected, resp.StatusCode, string(body))
+		}
+	})
+
+	t.Run("Body size limit after transcoding", func(t *testing.T) {
+		app := fiber.New()
+		app.Post("/", validator.New(validator.Config{
+			MaxBodySize: 16,
+			Transcode:   &validator.Transcode{},
+		}), func(c *fiber.Ctx) error {
+			return c.SendString("OK")
+		})
+
+		// Every Latin-1 character takes two bytes in UTF-8, so the 16 bytes grow to 24 bytes.
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"b":"`+strings.Repeat("\xe9", 8)+`"}`))
+		req.Header.Set("Content-Type", "application/json; charset=ISO-8859-1")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error reading response body: %v", err)
+		}
+
+		expected := `{"error":"Request body must not exceed 16 bytes","code":"BODY_TOO_LARGE","rule":"Transcode","params":{"max":16}}`
+		if resp.StatusCode != http.StatusRequestEntityTooLarge || string(body) != expected {
+			t.Errorf("Expected status %d and body '%s', got %d and '%s'", http.StatusRequestEntityTooLarge, expected, resp.StatusCode, string(body))
+		}
+	})
+
+	t.Run("Localized messages", func(t *testing.T) {
+		l := validator.NewLocalizer()
+		e := &validator.Error{Code: validator.CodeUnsupportedCharset, Message: "Unsupported charset 'x-bogus'", Params: map[string]interface{}{"charset": "x-bogus"}}
+		for tag, expected := range map[language.Tag]string{
+			language.English:    "Unsupported charset 'x-bogus'",
+			language.Indonesian: "Charset 'x-bogus' tidak didukung",
+			language.German:     "Nicht unterstützter Zeichensatz 'x-bogus'",
+		} {
+			if got := l.Localize(e, tag); got != expected {
+				t.Errorf("Expected message '%s', got '%s'", expected, got)
+			}
+		}
+	})
+}
+
+func TestContentTypes(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"name"}},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+	app.Post("/allowed", validator.New(validator.Config{
+		AllowedContentTypes: []string{fiber.MIMEApplicationJSON, "text/*"},
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"name"}, LegacyTextScan: true},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+	app.Post("/strict", validator.New(validator.Config{
+		AllowedContentTypes: []string{fiber.MIMEApplicationJSON, "text/*"},
+		Rules: []validator.Restrictor{
+			validator.RestrictUnicode{Fields: []string{"name"}},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+	app.Post("/rule", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictStringLength{
+				Fields:              []string{"name"},
+				MaxLength:           ptr(8),
+				AllowedContentTypes: []string{validator.MIMEApplicationNDJSON, "Application/YAML"},
+			},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		path           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Octet stream scanned as text",
+			path:           "/",
+			contentType:    "application/octet-stream",
+			requestBody:    "name=gøpher",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Unicode characters are not allowed in the 'name' field",
+		},
+		{
+			name:           "Missing Content-Type scanned as text",
+			path:           "/",
+			requestBody:    "name=gøpher",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Unicode characters are not allowed in the 'name' field",
+		},
+		{
+			name:           "Form scanned as text",
+			path:           "/",
+			contentType:    fiber.MIMEApplicationForm,
+			requestBody:    "name=gopher",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Empty body",
+			path:           "/",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Allowed JSON",
+			path:           "/allowed",
+			contentType:    fiber.MIMEApplicationJSONCharsetUTF8,
+			requestBody:    `{"name":"gopher"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Allowed media range with legacy text scan",
+			path:           "/allowed",
+			contentType:    fiber.MIMETextPlainCharsetUTF8,
+			requestBody:    "name=gøpher",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Unicode characters are not allowed in the 'name' field",
+		},
+		{
+			name:           "Allowed media range without legacy text scan",
+			path:           "/strict",
+			contentType:    fiber.MIMETextPlainCharsetUTF8,
+			requestBody:    "name=gopher",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   "Unsupported media type 'text/plain'",
+		},
+		{
+			name:           "Disallowed XML",
+			path:           "/allowed",
+			contentType:    fiber.MIMEApplicationXML,
+			requestBody:    `<request><name>gopher</name></request>`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `<xmlError><error>Unsupported media type &#39;application/xml&#39;</error><code>UNSUPPORTED_MEDIA_TYPE</code><rule>AllowedContentTypes</rule><params><param name="mediaType">application/xml</param></params></xmlError>`,
+		},
+		{
+			name:           "Rule allows NDJSON",
+			path:           "/rule",
+			contentType:    validator.MIMEApplicationNDJSON,
+			requestBody:    "{\"name\":\"gopher\"}\n{\"name\":\"gophers\"}\n",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Rule allows YAML",
+			path:           "/rule",
+			contentType:    validator.MIMEApplicationYAML,
+			requestBody:    "name: gopher\n",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Rule rejects JSON",
+			path:           "/rule",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":"gopher"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `{"error":"Unsupported media type 'application/json'","code":"UNSUPPORTED_MEDIA_TYPE","rule":"RestrictStringLength","params":{"mediaType":"application/json"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+
+	t.Run("Localized messages", func(t *testing.T) {
+		l := validator.NewLocalizer()
+		e := &validator.Error{Code: validator.CodeUnsupportedMediaType, Message: "Unsupported media type 'text/plain'", Params: map[string]interface{}{"mediaType": "text/plain"}}
+		for tag, expected := range map[language.Tag]string{
+			language.English:  "Unsupported media type 'text/plain'",
+			language.Japanese: "メディアタイプ 'text/plain' はサポートされていません",
+			language.German:   "Nicht unterstützter Medientyp 'text/plain'",
+		} {
+			if got := l.Localize(e, tag); got != expected {
+				t.Errorf("Expected message '%s', got '%s'", expected, got)
+			}
+		}
+	})
+}
+
+// uploadFile is a file of a multipart/form-data request body.
+type uploadFile struct {
+	field    string
+	filename string
+	content  string
+}
+
+// multipartBody encodes files as a multipart/form-data request body. File names are sent as they are,
+// and file names starting with "*=" are sent as an extended (RFC 2231) filename* parameter.
+func multipartBody(t *testing.T, files ...uploadFile) (string, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, f := range files {
+		header := make(textproto.MIMEHeader)
+		disposition := fmt.Sprintf(`form-data; name="%s"; filename="%s"`, f.field, f.filename)
+		if strings.HasPrefix(f.filename, "*=") {
+			disposition = fmt.Sprintf(`form-data; name="%s"; filename%s`, f.field, f.filename)
+		}
+		header.Set("Content-Disposition", disposition)
+		header.Set("Content-Type", "image/png")
+		part, err := w.CreatePart(header)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := io.WriteString(part, f.content); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return w.FormDataContentType(), &buf
+}
+
+func TestRestrictFiles(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	heic := "\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"
+
+	app := fiber.New()
+	app.Post("/", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictFiles{
+				Fields:            []string{"avatar", "photos"},
+				MaxFiles:          2,
+				MaxFileSize:       64,
+				MaxTotalSize:      100,
+				AllowedExtensions: []string{".png", "HEIC"},
+				AllowedFileTypes:  []string{"image/png", "image/heic"},
+				Signatures: []validator.FileSignature{
+					{ContentType: "image/heic", Offset: 4, Magic: []byte("ftypheic")},
+					// Signatures with a negative offset are ignored instead of panicking.
+					{ContentType: "image/png", Offset: -8, Magic: []byte("<?php")},
+				},
+			},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		files          []uploadFile
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid PNG",
+			files:          []uploadFile{{"avatar", "avatar.png", png}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Valid HEIC from the signature table",
+			files:          []uploadFile{{"photos", "IMG_0001.HEIC", heic}, {"photos", "IMG_0002.heic", heic}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Unchecked field",
+			files:          []uploadFile{{"attachment", "notes.txt", "hello"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Text disguised as PNG",
+			files:          []uploadFile{{"avatar", "avatar.png", "<?php echo 'hello'; ?>"}},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   "Files in the 'avatar' field must be of one of the allowed types",
+		},
+		{
+			name:           "Extension not allowed",
+			files:          []uploadFile{{"avatar", "avatar.png.exe", png}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Files in the 'avatar' field must have one of the allowed extensions",
+		},
+		{
+			name:           "Too many files",
+			files:          []uploadFile{{"photos", "1.png", png}, {"photos", "2.png", png}, {"photos", "3.png", png}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The 'photos' field must not contain more than 2 files",
+		},
+		{
+			name:           "File too large",
+			files:          []uploadFile{{"avatar", "avatar.png", png + strings.Repeat("\x00", 64)}},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Files in the 'avatar' field must not exceed 64 bytes",
+		},
+		{
+			name: "Files too large in total",
+			files: []uploadFile{
+				{"avatar", "avatar.png", png + strings.Repeat("\x00", 40)},
+				{"photos", "1.png", png + strings.Repeat("\x00", 40)},
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Uploaded files must not exceed 100 bytes in total",
+		},
+		{
+			name:           "Path separator in the file name",
+			files:          []uploadFile{{"avatar", "../../avatar.png", png}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The 'avatar' field contains a file with an invalid name",
+		},
+		{
+			name:           "Bidirectional control character in the file name",
+			files:          []uploadFile{{"avatar", "avatar\u202egnp.png", png}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The 'avatar' field contains a file with an invalid name",
+		},
+		{
+			name:           "NUL byte in the file name",
+			files:          []uploadFile{{"avatar", "*=UTF-8''avatar.png%00.exe", png}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The 'avatar' field contains a file with an invalid name",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contentType, body := multipartBody(t, tc.files...)
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			req.Header.Set("Content-Type", contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(respBody) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(respBody))
+			}
+		})
+	}
+
+	t.Run("Sniffed content type", func(t *testing.T) {
+		var got error
+		app := fiber.New()
+		app.Post("/", validator.New(validator.Config{
+			Rules: []validator.Restrictor{
+				validator.RestrictFiles{AllowedFileTypes: []string{"image/*"}},
+			},
+			ErrorHandler: func(c *fiber.Ctx, err error) error {
+				got = err
+				return validator.DefaultErrorHandler(c, err)
+			},
+		}), func(c *fiber.Ctx) error {
+			return c.SendString("OK")
+		})
+
+		contentType, body := multipartBody(t, uploadFile{"document", "report.png", "%PDF-1.7"})
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set("Content-Type", contentType)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		var ve *validator.Error
+		if !errors.As(got, &ve) || !errors.Is(got, validator.ErrFileNotAllowed) {
+			t.Fatalf("Expected a *validator.Error matching ErrFileNotAllowed, got %v", got)
+		}
+		if ve.Params["contentType"] != "application/pdf" || ve.Params["filename"] != "report.png" {
+			t.Errorf("Unexpected params %v", ve.Params)
+		}
+	})
+}
+
+// encodeImage encodes an image of the given size in the given format.
+func encodeImage(t *testing.T, format string, width, height int) string {
+	t.Helper()
+	img := image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{color.Black, color.White})
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	case "animated gif":
+		err = gif.EncodeAll(&buf, &gif.GIF{Image: []*image.Paletted{img, img}, Delay: []int{10, 10}})
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+// pngChunk encodes a PNG chunk with its CRC.
+func pngChunk(typ string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, typ...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// forgePNG returns a small PNG image whose header declares the given size, and an animation control chunk if animated.
+func forgePNG(t *testing.T, width, height int, animated bool) string {
+	t.Helper()
+	data := []byte(encodeImage(t, "png", 1, 1))
+	// The IHDR chunk follows the 8-byte signature and is 25 bytes long.
+	ihdr := append([]byte(nil), data[16:29]...)
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(width))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(height))
+
+	forged := append([]byte(nil), data[:8]...)
+	forged = append(forged, pngChunk("IHDR", ihdr)...)
+	if animated {
+		forged = append(forged, pngChunk("acTL", []byte{0, 0, 0, 2, 0, 0, 0, 0})...)
+	}
+	return string(append(forged, data[33:]...))
+}
+
+func TestRestrictImage(t *testing.T) {
+	app := fiber.New()
+	app.Post("/avatar", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictImage{
+				Fields:         []string{"avatar"},
+				AllowedFormats: []string{"png", "jpeg", "gif"},
+				MaxWidth:       4096,
+				MaxHeight:      4096,
+				MaxPixels:      4000000,
+			},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+	app.Post("/banner", validator.New(validator.Config{
+		Rules: []validator.Restrictor{
+			validator.RestrictImage{AllowedFormats: []string{"png"}, AllowAnimated: true},
+		},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		path           string
+		files          []uploadFile
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid PNG",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.png", encodeImage(t, "png", 64, 64)}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Valid JPEG",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.jpg", encodeImage(t, "jpeg", 64, 64)}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Valid still GIF",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.gif", encodeImage(t, "gif", 64, 64)}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Animated GIF",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.gif", encodeImage(t, "animated gif", 64, 64)}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Images in the 'avatar' field must not be animated",
+		},
+		{
+			name:           "Animated PNG",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.png", forgePNG(t, 64, 64, true)}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Images in the 'avatar' field must not be animated",
+		},
+		{
+			name:           "Decompression bomb",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.png", forgePNG(t, 100000, 100000, false)}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Images in the 'avatar' field must not be wider than 4096 pixels",
+		},
+		{
+			name:           "Too tall",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.png", forgePNG(t, 16, 5000, false)}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Images in the 'avatar' field must not be taller than 4096 pixels",
+		},
+		{
+			name:           "Too many pixels",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.png", forgePNG(t, 4000, 4000, false)}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Images in the 'avatar' field must not exceed 4000000 pixels",
+		},
+		{
+			name:           "Not an image",
+			path:           "/avatar",
+			files:          []uploadFile{{"avatar", "avatar.png", "%PDF-1.7"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "The 'avatar' field contains a file that is not a valid image",
+		},
+		{
+			name:           "Format not allowed",
+			path:           "/banner",
+			files:          []uploadFile{{"banner", "banner.gif", encodeImage(t, "gif", 64, 64)}},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   "Images in the 'banner' field must be in one of the allowed formats",
+		},
+		{
+			name:           "Animation allowed",
+			path:           "/banner",
+			files:          []uploadFile{{"banner", "banner.png", forgePNG(t, 64, 64, true)}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contentType, body := multipartBody(t, tc.files...)
+			req := httptest.NewRequest(http.MethodPost, tc.path, body)
+			req.Header.Set("Content-Type", contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(respBody) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(respBody))
+			}
+		})
+	}
+}
+
+func TestRestrictType(t *testing.T) {
+	cfg, err := validator.LoadConfig(strings.NewReader(`
+rules:
+  - type: RestrictType
+    fields: [tags]
+    jsonType: array
+  - type: RestrictType
+    fields: [nickname]
+    jsonType: string
+    nullable: true
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg.Rules = append(cfg.Rules,
+		validator.RestrictType{Fields: []string{"age"}, Type: validator.TypeInteger},
+		validator.RestrictType{Fields: []string{"active"}, Type: validator.TypeBoolean},
+		validator.RestrictType{Fields: []string{"address"}, Type: validator.TypeObject},
+		validator.RestrictUnicode{Fields: []string{"name"}, StrictTypes: true},
+		validator.RestrictStringLength{Fields: []string{"bio"}, MaxLength: ptr(8), StrictTypes: true},
+		validator.RestrictStringLength{Fields: []string{"title"}, MaxLength: ptr(8)},
+	)
+
+	app := fiber.New()
+	app.Post("/", validator.New(cfg), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid types",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":["a"],"nickname":null,"age":42,"active":false,"address":{"city":"Bali"},"name":"gopher","bio":null}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Array instead of a string",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"nickname":["gopher"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'nickname' field must be of type string or null","code":"TYPE_MISMATCH","field":"nickname","rule":"RestrictType","params":{"actual":"array","nullable":true,"type":"string"}}`,
+		},
+		{
+			name:           "Null where not nullable",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":null}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'tags' field must be of type array","code":"TYPE_MISMATCH","field":"tags","rule":"RestrictType","params":{"actual":"null","type":"array"}}`,
+		},
+		{
+			name:           "Fractional number instead of an integer",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"age":42.5}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'age' field must be of type integer","code":"TYPE_MISMATCH","field":"age","rule":"RestrictType","params":{"actual":"number","type":"integer"}}`,
+		},
+		{
+			name:           "String instead of a boolean in YAML",
+			contentType:    validator.MIMEApplicationYAML,
+			requestBody:    "active: \"yes\"\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "error: The 'active' field must be of type boolean\ncode: TYPE_MISMATCH\nfield: active\nrule: RestrictType\nparams:\n    actual: string\n    type: boolean\n",
+		},
+		{
+			name:           "Number bypassing RestrictUnicode with StrictTypes",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"name":123}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'name' field must be of type string or null","code":"TYPE_MISMATCH","field":"name","rule":"RestrictUnicode","params":{"actual":"number","nullable":true,"type":"string"}}`,
+		},
+		{
+			name:           "Array bypassing RestrictStringLength with StrictTypes",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"bio":["a very long biography"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'bio' field must be of type string or null","code":"TYPE_MISMATCH","field":"bio","rule":"RestrictStringLength","params":{"actual":"array","nullable":true,"type":"string"}}`,
+		},
+		{
+			name:           "Array without StrictTypes",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"title":["a very long title"]}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "XML values are not typed",
+			contentType:    fiber.MIMEApplicationXML,
+			requestBody:    `<request><age>forty-two</age></request>`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+
+	t.Run("OpenAPI schema", func(t *testing.T) {
+		schema := validator.OpenAPISchema(validator.Config{Rules: []validator.Restrictor{
+			validator.RestrictType{Fields: []string{"age"}, Type: validator.TypeInteger},
+			validator.RestrictUnicode{Fields: []string{"name"}, StrictTypes: true},
+		}})
+		got, err := json.Marshal(schema["properties"])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"age":{"type":"integer"},"name":{"nullable":true,"pattern":"^[\\x00-\\x7F]*$","type":"string"}}`
+		if string(got) != expected {
+			t.Errorf("Expected properties '%s', got '%s'", expected, string(got))
+		}
+	})
+
+	t.Run("Localized messages", func(t *testing.T) {
+		l := validator.NewLocalizer()
+		e := &validator.Error{Code: validator.CodeTypeMismatch, Field: "age", Message: "The 'age' field must be of type integer", Params: map[string]interface{}{"type": "integer", "actual": "string"}}
+		for tag, expected := range map[language.Tag]string{
+			language.Indonesian: "Kolom 'age' harus bertipe integer",
+			language.Japanese:   "'age' フィールドは integer 型でなければなりません",
+			language.German:     "Das Feld 'age' muss vom Typ integer sein",
+		} {
+			if got := l.Localize(e, tag); got != expected {
+				t.Errorf("Expected message '%s', got '%s'", expected, got)
+			}
+		}
+
+		nullable := &validator.Error{Code: validator.CodeTypeMismatch, Field: "nickname", Message: "The 'nickname' field must be of type string or null", Params: map[string]interface{}{"type": "string", "actual": "array", "nullable": true}}
+		for tag, expected := range map[language.Tag]string{
+			language.English:    "The 'nickname' field must be of type string or null",
+			language.Indonesian: "Kolom 'nickname' harus bertipe string atau null",
+			language.Japanese:   "'nickname' フィールドは string または null 型でなければなりません",
+			language.German:     "Das Feld 'nickname' muss vom Typ string oder null sein",
+		} {
+			if got := l.Localize(nullable, tag); got != expected {
+				t.Errorf("Expected message '%s', got '%s'", expected, got)
+			}
+		}
+	})
+}
+
+// wrappingRule is a custom element rule that wraps the errors of a built-in rule.
+type wrappingRule struct {
+	Fields []string
+}
+
+func (r wrappingRule) Restrict(c *fiber.Ctx) error {
+	if err := (validator.RestrictNumberOnly{Fields: r.Fields, Max: ptr(100)}).Restrict(c); err != nil {
+		return fmt.Errorf("wrapped: %w", err)
+	}
+	return nil
+}
+
+func TestRestrictArray(t *testing.T) {
+	cfg, err := validator.LoadConfig(strings.NewReader(`
+rules:
+  - type: RestrictArray
+    fields: [tags]
+    minItems: 1
+    maxItems: 3
+    uniqueItems: true
+    each:
+      - type: RestrictUnicode
+        strictTypes: true
+      - type: RestrictStringLength
+        maxLength: 8
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg.Rules = append(cfg.Rules,
+		validator.RestrictArray{Fields: []string{"matrix"}, Each: []validator.Restrictor{
+			validator.RestrictArray{MaxItems: ptr(2), Each: []validator.Restrictor{
+				validator.RestrictNumberOnly{Max: ptr(9)},
+			}},
+		}},
+		validator.RestrictArray{Fields: []string{"codes"}, Each: []validator.Restrictor{
+			&validator.RestrictStringLength{MaxLength: ptr(4)},
+		}},
+		validator.RestrictArray{Fields: []string{"scores"}, Each: []validator.Restrictor{
+			wrappingRule{},
+		}},
+	)
+
+	app := fiber.New()
+	app.Post("/", validator.New(cfg), func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	testCases := []struct {
+		name           string
+		contentType    string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid arrays",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":["go","fiber"],"matrix":[[1,2],[3]]}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Missing and null arrays",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":null}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			name:           "Too few items",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'tags' field must contain at least 1 items","code":"TOO_FEW_ITEMS","field":"tags","rule":"RestrictArray","params":{"count":0,"min":1}}`,
+		},
+		{
+			name:           "Too many items",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":["a","b","c","d"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'tags' field must not contain more than 3 items","code":"TOO_MANY_ITEMS","field":"tags","rule":"RestrictArray","params":{"count":4,"max":3}}`,
+		},
+		{
+			name:           "Duplicate items",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":["go","fiber","go"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'tags' field must not contain duplicate items","code":"DUPLICATE_ITEMS","field":"tags","rule":"RestrictArray","params":{"index":2}}`,
+		},
+		{
+			name:           "Unicode item",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":["go","ｇｏ"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Unicode characters are not allowed in the 'tags[1]' field","code":"UNICODE_NOT_ALLOWED","field":"tags[1]","rule":"RestrictArray","params":{"index":1}}`,
+		},
+		{
+			name:           "Item too long",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":["go","gopherfiber"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'tags[1]' field must not exceed 8 characters","code":"MAX_LENGTH_EXCEEDED","field":"tags[1]","rule":"RestrictArray","params":{"index":1,"max":8}}`,
+		},
+		{
+			name:           "Item of the wrong type",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":["go",42]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'tags[1]' field must be of type string or null","code":"TYPE_MISMATCH","field":"tags[1]","rule":"RestrictArray","params":{"actual":"number","index":1,"nullable":true,"type":"string"}}`,
+		},
+		{
+			name:           "Not an array",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"tags":"go"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'tags' field must be of type array or null","code":"TYPE_MISMATCH","field":"tags","rule":"RestrictArray","params":{"actual":"string","nullable":true,"type":"array"}}`,
+		},
+		{
+			name:           "Nested array item",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"matrix":[[1,2],[3,10]]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'matrix[1][1]' field must not exceed 9","code":"MAX_VALUE_EXCEEDED","field":"matrix[1][1]","rule":"RestrictArray","params":{"index":1,"max":9}}`,
+		},
+		{
+			name:           "Pointer element rule",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"codes":["go","gopher"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'codes[1]' field must not exceed 4 characters","code":"MAX_LENGTH_EXCEEDED","field":"codes[1]","rule":"RestrictArray","params":{"index":1,"max":4}}`,
+		},
+		{
+			name:           "Wrapped element error",
+			contentType:    fiber.MIMEApplicationJSON,
+			requestBody:    `{"scores":[50,101]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"The 'scores[1]' field must not exceed 100","code":"MAX_VALUE_EXCEEDED","field":"scores[1]","rule":"RestrictArray","params":{"index":1,"max":100}}`,
+		},
+		{
+			name:           "Items of YAML request bodies",
+			contentType:    validator.MIMEApplicationYAML,
+			requestBody:    "tags: [go, gopherfiber]\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "error: The 'tags[1]' field must not exceed 8 characters\ncode: MAX_LENGTH_EXCEEDED\nfield: tags[1]\nrule: RestrictArray\nparams:\n    index: 1\n    max: 8\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error reading response body: %v", err)
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body '%s', got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+
+	t.Run("Result", func(t *testing.T) {
+		app := fiber.New()
+		app.Post("/", validator.New(validator.Config{
+			Rules: []validator.Restrictor{
+				validator.RestrictArray{Fields: []string{"tags"}, Each: []validator.Restrictor{
+					validator.RestrictUnicode{},
+					wrappingRule{},
+				}},
+			},
+			RecordValues: true,
+		}), func(c *fiber.Ctx) error {
+			result := validator.ResultFrom(c)
+			var fields []string
+			for _, f := range result.Fields {
+				fields = append(fields, f.Field+":"+f.Rule)
+			}
+			return c.SendString(fmt.Sprintf("fields=%s values=%v", strings.Join(fields, ","), result.Values))
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"tags":["1","2"]}`))
+		req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error reading response body: %v", err)
+		}
+
+		// The items are checked by the element rules, but only the array is a field of the request body.
+		expected := "fields=tags:RestrictArray values=map[tags:[1 2]]"
+		if string(body) != expected {
+			t.Errorf("Expected body '%s', got '%s'", expected, string(body))
+		}
+	})
+
+	t.Run("Localized messages", func(t *testing.T) {
+		l := validator.NewLocalizer()
+		e := &validator.Error{Code: validator.CodeTooFewItems, Field: "tags", Params: map[string]interface{}{"min": 1}}
+		expected := "Das Feld 'tags' muss mindestens 1 Einträge enthalten"
+		if got := l.Localize(e, language.German); got != expected {
+			t.Errorf("Expected message '%s', got '%s'", expected, got)
+		}
+	})
+
+	t.Run("OpenAPI schema", func(t *testing.T) {
+		schema := validator.OpenAPISchema(cfg)
+		got, err := json.Marshal(schema["properties"])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"codes":{"items":{"maxLength":4},"type":"array"},"matrix":{"items":{"items":{"maximum":9,"pattern":"^[0-9]*$"},"maxItems":2,"type":"array"},"type":"array"},"scores":{"type":"array"},"tags":{"items":{"maxLength":8,"nullable":true,"pattern":"^[\\x00-\\x7F]*$","type":"string"},"maxItems":3,"minItems":1,"type":"array","uniqueItems":true}}`
+		if string(got) != expected {
+			t.Errorf("Expected properties '%s', got '%s'", expected, string(got))
+		}
+	})
+}